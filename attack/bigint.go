@@ -0,0 +1,182 @@
+package attack
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// ErrModuliNotCoprime is returned by RecoverRSABroadcastPlaintext and
+// ExploitSmallSubgroupConfinement when the moduli of the residues they combine
+// are not pairwise coprime.
+var ErrModuliNotCoprime = errors.New("attack: moduli not pairwise coprime")
+
+// ErrInvalidModulus is returned when a modulus is missing or too small.
+var ErrInvalidModulus = errors.New("attack: invalid modulus")
+
+// crt uses the Chinese Remainder Theorem to solve for the unique x in [0, M)
+// such that x ≡ residues[i] (mod moduli[i]) for all i, where M is the product
+// of the moduli. It returns ErrInvalidModulus if any modulus is nil or not
+// positive and ErrModuliNotCoprime if the moduli are not pairwise coprime. It
+// panics if len(residues) != len(moduli).
+func crt(residues, moduli []*big.Int) (x, m *big.Int, err error) {
+	if len(residues) != len(moduli) {
+		panic("crt: residue and moduli counts differ")
+	}
+
+	m = big.NewInt(1)
+	for i, n := range moduli {
+		if n == nil || n.Sign() <= 0 {
+			return nil, nil, ErrInvalidModulus
+		}
+		if residues[i] == nil {
+			return nil, nil, fmt.Errorf("attack: missing residue %d", i)
+		}
+		m.Mul(m, n)
+	}
+
+	x = new(big.Int)
+	for i, n := range moduli {
+		// ms is the product of every modulus except n.
+		ms := new(big.Int).Quo(m, n)
+		inv, err := rsa.InvMod(ms, n)
+		if err != nil {
+			return nil, nil, ErrModuliNotCoprime
+		}
+		term := new(big.Int).Mul(residues[i], ms)
+		term.Mul(term, inv)
+		x.Add(x, term)
+	}
+
+	return x.Mod(x, m), m, nil
+}
+
+// root returns the integer n-th root of x, floor(x^(1/n)), and whether it is
+// exact. It panics if x is negative or n is less than 1.
+func root(x *big.Int, n int) (r *big.Int, exact bool) {
+	if x.Sign() < 0 {
+		panic("root: negative x")
+	}
+	if n < 1 {
+		panic("root: n not > 0")
+	}
+	if x.Sign() == 0 || n == 1 {
+		return new(big.Int).Set(x), true
+	}
+
+	// Newton's method, starting from a power of two guaranteed to be >= the
+	// root. Each iteration decreases r until it reaches floor(x^(1/n)).
+	bn := big.NewInt(int64(n))
+	bnMinusOne := big.NewInt(int64(n - 1))
+	r = new(big.Int).Lsh(big.NewInt(1), uint((x.BitLen()+n-1)/n))
+	next, tmp := new(big.Int), new(big.Int)
+	for {
+		// next = ((n-1)*r + x/r^(n-1)) / n
+		tmp.Exp(r, bnMinusOne, nil)
+		tmp.Quo(x, tmp)
+		next.Mul(r, bnMinusOne)
+		next.Add(next, tmp)
+		next.Quo(next, bn)
+		if next.Cmp(r) >= 0 {
+			break
+		}
+		r.Set(next)
+	}
+
+	return r, tmp.Exp(r, bn, nil).Cmp(x) == 0
+}
+
+// ErrNoDiscreteLog is returned by DiscreteLogInRange when no discrete
+// logarithm is found in the given range.
+var ErrNoDiscreteLog = errors.New("attack: no discrete logarithm in range")
+
+// DiscreteLogInRange returns x in [a, b] such that g^x = y mod p, using
+// Pollard's kangaroo (lambda) algorithm, along with the number of jumps taken.
+// It returns ErrNoDiscreteLog if there is no such x, although, the algorithm
+// being probabilistic, it may occasionally do so even if there is.
+//
+// A tame kangaroo starts at g^b and takes n jumps, each of 2^(y mod k) for
+// its current position y, remembering where it stops. A wild kangaroo then
+// starts at y and jumps the same way until it passes the tame kangaroo's
+// distance. If it lands anywhere the tame kangaroo did, it follows the same
+// path from then on and ends up in the tame kangaroo's trap, revealing x.
+//
+// The mean jump is m = (2^k-1)/k, so the tame kangaroo travels about n*m and
+// the wild kangaroo needs about n + (b-x)/m jumps to reach the trap, or
+// n + (b-a)/m to give up. KangarooParams chooses m of about sqrt(b-a)/2 and
+// n = 4m, which puts the trap about b-a beyond b and makes the total number of
+// jumps at most about 6*sqrt(b-a).
+//
+// It panics if k is not in [1, 63] or n is less than 1.
+func DiscreteLogInRange(p, g, y, a, b *big.Int, k, n int) (x *big.Int, jumps uint64, err error) {
+	if k < 1 || k > 63 {
+		panic("k not in range [1, 63]")
+	}
+	if n < 1 {
+		panic("n not > 0")
+	}
+
+	// steps[i] = g^(2^i) mod p
+	steps := make([]*big.Int, k)
+	for i := range steps {
+		steps[i] = new(big.Int).Exp(g, new(big.Int).Lsh(big.NewInt(1), uint(i)), p)
+	}
+	jump := func(pos *big.Int) int {
+		return int(pos.Uint64() % uint64(k))
+	}
+
+	// The tame kangaroo.
+	xT := new(big.Int)
+	yT := new(big.Int).Exp(g, b, p)
+	for i := 0; i < n; i++ {
+		j := jump(yT)
+		xT.Add(xT, new(big.Int).Lsh(big.NewInt(1), uint(j)))
+		yT.Mul(yT, steps[j]).Mod(yT, p)
+		jumps++
+	}
+
+	// The wild kangaroo.
+	limit := new(big.Int).Sub(b, a)
+	limit.Add(limit, xT)
+	xW := new(big.Int)
+	yW := new(big.Int).Set(y)
+	for xW.Cmp(limit) <= 0 {
+		if yW.Cmp(yT) == 0 {
+			x = new(big.Int).Add(b, xT)
+			return x.Sub(x, xW), jumps, nil
+		}
+		j := jump(yW)
+		xW.Add(xW, new(big.Int).Lsh(big.NewInt(1), uint(j)))
+		yW.Mul(yW, steps[j]).Mod(yW, p)
+		jumps++
+	}
+
+	return nil, jumps, ErrNoDiscreteLog
+}
+
+// ExpectedKangarooJumps returns the number of jumps DiscreteLogInRange takes,
+// with parameters k and n, over an interval of the given width when it fails
+// to find a discrete logarithm, which is the most it takes when it succeeds.
+func ExpectedKangarooJumps(width *big.Int, k, n int) uint64 {
+	mean := (uint64(1)<<k - 1) / uint64(k)
+	wild := new(big.Int).Quo(width, new(big.Int).SetUint64(mean))
+	return 2*uint64(n) + wild.Uint64()
+}
+
+// KangarooParams returns the parameters k and n for DiscreteLogInRange over an
+// interval of the given width: the smallest k for which the mean jump,
+// (2^k-1)/k, is at least sqrt(width)/2, and n = 4 times the mean jump.
+func KangarooParams(width *big.Int) (k, n int) {
+	target := new(big.Int).Sqrt(width)
+	target.Rsh(target, 1)
+	mean := new(big.Int)
+	for k = 1; k < 63; k++ {
+		mean.SetUint64((1<<k - 1) / uint64(k))
+		if mean.Cmp(target) >= 0 {
+			break
+		}
+	}
+	return k, int(4 * mean.Uint64())
+}
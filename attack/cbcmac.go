@@ -0,0 +1,85 @@
+package attack
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"errors"
+
+	"github.com/saclark/cryptopals/cbcmac"
+	"github.com/saclark/cryptopals/pkcs7"
+	"github.com/saclark/cryptopals/xor"
+)
+
+// ForgeCBCMACIV returns an IV under which forged has the same CBC-MAC that
+// message has under iv, given that the two differ only in their first block.
+// This works against CBC-MAC schemes that let the sender choose the IV.
+//
+// The first block is XORed with the IV before being encrypted, so any change
+// to the first block can be cancelled out by the same change to the IV.
+func ForgeCBCMACIV(message, iv, forged []byte) ([]byte, error) {
+	blockSize := len(iv)
+	if len(message) != len(forged) {
+		return nil, errors.New("attack: forged message length differs from original")
+	}
+	n := blockSize
+	if len(message) < n {
+		n = len(message)
+	}
+	if !bytes.Equal(message[n:], forged[n:]) {
+		return nil, errors.New("attack: forged message differs from original after first block")
+	}
+
+	forgedIV := make([]byte, blockSize)
+	copy(forgedIV, iv)
+	diff := make([]byte, n)
+	xor.BytesFixed(diff, message[:n], forged[:n])
+	xor.BytesFixed(forgedIV[:n], forgedIV[:n], diff)
+	return forgedIV, nil
+}
+
+// ExtendCBCMAC returns a message with the CBC-MAC mac2, given a message1 with
+// the CBC-MAC mac1 and a message2 with the CBC-MAC mac2, all under the same
+// fixed iv. The returned message is message1, its PKCS#7 padding, and then
+// message2 with its first block XORed with mac1 and iv. Message2 must be at
+// least one block long.
+//
+// CBC-MAC'ing the padded message1 leaves the CBC chain in state mac1. XORing
+// the next block with mac1 (and the fixed IV) cancels that state out, so the
+// chain continues exactly as if it were MAC'ing message2 from the start.
+func ExtendCBCMAC(iv, message1, mac1, message2 []byte) ([]byte, error) {
+	blockSize := len(iv)
+	if len(message2) < blockSize {
+		return nil, errors.New("attack: second message shorter than a block")
+	}
+
+	forged := pkcs7.Pad(append([]byte(nil), message1...), blockSize)
+	first := make([]byte, blockSize)
+	xor.BytesFixed(first, message2[:blockSize], mac1)
+	xor.BytesFixed(first, first, iv)
+	forged = append(forged, first...)
+	return append(forged, message2[blockSize:]...), nil
+}
+
+// ForgeCBCMACCollision returns a message that begins with the PKCS#7 padded
+// prefix, followed by a single block, and whose CBC-MAC under the known block
+// cipher and IV is target. This breaks CBC-MAC as a hash function: anyone who
+// knows the key can produce a message with any chosen prefix and any MAC.
+//
+// A message ending in a full block is MAC'd with an additional full block of
+// padding, P, so we need E(E(X ⊕ s) ⊕ P) = target, where s is the CBC state
+// after the padded prefix, which is just its CBC-MAC. Decrypting backwards from
+// the target gives X = D(D(target) ⊕ P) ⊕ s.
+func ForgeCBCMACCollision(block cipher.Block, iv, target, prefix []byte) []byte {
+	blockSize := block.BlockSize()
+	forged := pkcs7.Pad(append([]byte(nil), prefix...), blockSize)
+	state := cbcmac.Sum(block, iv, prefix)
+	fullPadding := pkcs7.Pad(nil, blockSize)
+
+	x := make([]byte, blockSize)
+	block.Decrypt(x, target)
+	xor.BytesFixed(x, x, fullPadding)
+	block.Decrypt(x, x)
+	xor.BytesFixed(x, x, state)
+
+	return append(forged, x...)
+}
@@ -0,0 +1,69 @@
+package attack
+
+import (
+	"bytes"
+	"crypto/aes"
+	"testing"
+
+	"github.com/saclark/cryptopals/cbcmac"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+func TestForgeCBCMACIV(t *testing.T) {
+	block := testutil.Must(aes.NewCipher(testutil.MustRandomBytes(aes.BlockSize)))
+	iv := testutil.MustRandomBytes(aes.BlockSize)
+	message := []byte("from=1&to=1&amount=1000000")
+	mac := cbcmac.Sum(block, iv, message)
+
+	forged := []byte("from=2&to=1&amount=1000000")
+	forgedIV, err := ForgeCBCMACIV(message, iv, forged)
+	if err != nil {
+		t.Fatalf("forging IV: %v", err)
+	}
+
+	if !cbcmac.Verify(block, forgedIV, forged, mac) {
+		t.Fatal("want forged message to verify under forged IV")
+	}
+
+	if _, err := ForgeCBCMACIV(message, iv, []byte("from=1&to=1&amount=9000000")); err == nil {
+		t.Fatal("want error for change after first block, got nil")
+	}
+}
+
+func TestExtendCBCMAC(t *testing.T) {
+	block := testutil.Must(aes.NewCipher(testutil.MustRandomBytes(aes.BlockSize)))
+	iv := make([]byte, aes.BlockSize)
+	message1 := []byte("from=1&tx_list=2:100;3:200")
+	message2 := []byte("from=4&tx_list=4:1;4:1000000")
+	mac1 := cbcmac.Sum(block, iv, message1)
+	mac2 := cbcmac.Sum(block, iv, message2)
+
+	forged, err := ExtendCBCMAC(iv, message1, mac1, message2)
+	if err != nil {
+		t.Fatalf("extending: %v", err)
+	}
+
+	if !bytes.HasPrefix(forged, message1) || !bytes.HasSuffix(forged, message2[aes.BlockSize:]) {
+		t.Fatalf("unexpected forged message: '%x'", forged)
+	}
+	if !cbcmac.Verify(block, iv, forged, mac2) {
+		t.Fatal("want forged message to verify with second MAC")
+	}
+}
+
+func TestForgeCBCMACCollision(t *testing.T) {
+	block := testutil.Must(aes.NewCipher(testutil.MustRandomBytes(aes.BlockSize)))
+	iv := make([]byte, aes.BlockSize)
+	target := cbcmac.Sum(block, iv, []byte("the original message"))
+
+	for _, prefix := range []string{"", "short", "exactly 16 bytes", "a prefix that spans several blocks"} {
+		forged := ForgeCBCMACCollision(block, iv, target, []byte(prefix))
+
+		if !bytes.HasPrefix(forged, []byte(prefix)) {
+			t.Fatalf("want prefix: '%s', got: '%s'", prefix, forged)
+		}
+		if got := cbcmac.Sum(block, iv, forged); !bytes.Equal(target, got) {
+			t.Fatalf("prefix '%s': want: '%x', got: '%x'", prefix, target, got)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package attack
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrAmbiguousCompression is returned by ExploitCompressionOracle when no
+// single candidate byte compresses better than the rest.
+var ErrAmbiguousCompression = errors.New("attack: no candidate compresses best")
+
+// compressionPaddingBytes are prepended to guesses to move the length of the
+// compressed plaintext across block boundaries. They are distinct, so they
+// never form matches with each other, and are unlikely to occur in requests.
+var compressionPaddingBytes = func() []byte {
+	b := make([]byte, 128)
+	for i := range b {
+		b[i] = byte(0x80 + i)
+	}
+	return b
+}()
+
+// ExploitCompressionOracle recovers a secret that follows prefix in a
+// plaintext which is compressed before being encrypted, one byte at a time,
+// using an oracle that returns the length of the encryption of that plaintext
+// with a chosen payload included (a CRIME attack). Recovery stops, without
+// including it, at the first occurrence of terminator, or after maxLen bytes.
+// Every byte of the secret, and terminator, must be in alphabet.
+//
+// When the payload repeats the prefix and the secret recovered so far, along
+// with a guess at the next byte, it compresses better if the guess is right.
+// For a stream cipher that is enough to tell the guesses apart, as long as the
+// compression saves at least one byte. A block cipher hides small changes in
+// length, so for block sizes greater than 1 the guesses are prefixed with just
+// enough incompressible padding to push a wrong guess into a new block. The
+// right guess, being shorter, stays behind.
+//
+// Ties are broken by summing the lengths over every amount of padding up to
+// the block size and, failing that, by guessing an additional byte.
+//
+// When not nil, logf is used to log the attack's progress.
+//
+// It panics if blockSize is less than 1 or greater than 128.
+func ExploitCompressionOracle(
+	oracle func(payload []byte) int,
+	prefix []byte,
+	alphabet []byte,
+	terminator byte,
+	maxLen int,
+	blockSize int,
+	logf func(format string, a ...any),
+) ([]byte, error) {
+	if blockSize < 1 || blockSize > len(compressionPaddingBytes) {
+		panic("blockSize not in range [1, 128]")
+	}
+
+	padded := func(n int, guess []byte) []byte {
+		return append(append([]byte(nil), compressionPaddingBytes[:n]...), guess...)
+	}
+	sumScore := func(guess []byte) int {
+		total := 0
+		for n := 0; n < blockSize; n++ {
+			total += oracle(padded(n, guess))
+		}
+		return total
+	}
+
+	known := append([]byte(nil), prefix...)
+	for len(known)-len(prefix) < maxLen {
+		// Find the least padding that pushes a guess into a new block.
+		ref := append(append([]byte(nil), known...), alphabet[0])
+		n, refLen := 0, oracle(ref)
+		for n < blockSize-1 && oracle(padded(n, ref)) == refLen {
+			n++
+		}
+		score := func(guess []byte) int { return oracle(padded(n, guess)) }
+
+		best := bestCompressingBytes(known, alphabet, score)
+		if len(best) > 1 {
+			best = bestCompressingBytes(known, best, sumScore)
+		}
+		if len(best) > 1 {
+			best = bestCompressingPairs(known, best, alphabet, sumScore)
+		}
+		if len(best) != 1 {
+			return known[len(prefix):], ErrAmbiguousCompression
+		}
+
+		if best[0] == terminator {
+			break
+		}
+		known = append(known, best[0])
+		if logf != nil {
+			logf("%q\n", known[len(prefix):])
+		}
+	}
+
+	return known[len(prefix):], nil
+}
+
+// bestCompressingBytes returns the candidates which, appended to known, have
+// the lowest score.
+func bestCompressingBytes(known, candidates []byte, score func(guess []byte) int) []byte {
+	var best []byte
+	bestScore := math.MaxInt
+	guess := append(append([]byte(nil), known...), 0)
+	for _, c := range candidates {
+		guess[len(guess)-1] = c
+		s := score(guess)
+		switch {
+		case s < bestScore:
+			best, bestScore = []byte{c}, s
+		case s == bestScore:
+			best = append(best, c)
+		}
+	}
+	return best
+}
+
+// bestCompressingPairs returns the candidates which, appended to known and
+// followed by the best following byte from alphabet, have the lowest score.
+func bestCompressingPairs(known, candidates, alphabet []byte, score func(guess []byte) int) []byte {
+	var best []byte
+	bestScore := math.MaxInt
+	guess := append(append([]byte(nil), known...), 0, 0)
+	for _, c := range candidates {
+		guess[len(guess)-2] = c
+		for _, d := range alphabet {
+			guess[len(guess)-1] = d
+			s := score(guess)
+			switch {
+			case s < bestScore:
+				best, bestScore = []byte{c}, s
+			case s == bestScore && best[len(best)-1] != c:
+				best = append(best, c)
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,38 @@
+package attack
+
+import (
+	"bytes"
+	"compress/flate"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+func TestExploitCompressionOracle(t *testing.T) {
+	secret := []byte("s3cr3t0k3n")
+	alphabet := []byte("abcdefghijklmnopqrstuvwxyz0123456789;")
+	oracle := func(blockSize int) func([]byte) int {
+		return func(payload []byte) int {
+			var buf bytes.Buffer
+			w := testutil.Must(flate.NewWriter(&buf, flate.BestCompression))
+			testutil.Must(w.Write([]byte("GET / HTTP/1.1\ntoken=")))
+			testutil.Must(w.Write(secret))
+			testutil.Must(w.Write([]byte(";\n")))
+			testutil.Must(w.Write(payload))
+			if err := w.Close(); err != nil {
+				t.Fatalf("compressing: %v", err)
+			}
+			return (buf.Len()/blockSize + 1) * blockSize
+		}
+	}
+
+	for _, blockSize := range []int{1, 16} {
+		got, err := ExploitCompressionOracle(oracle(blockSize), []byte("token="), alphabet, ';', 32, blockSize, nil)
+		if err != nil {
+			t.Fatalf("block size %d: exploiting oracle: %v", blockSize, err)
+		}
+		if !bytes.Equal(secret, got) {
+			t.Fatalf("block size %d: want: '%s', got: '%s'", blockSize, secret, got)
+		}
+	}
+}
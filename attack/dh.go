@@ -0,0 +1,168 @@
+package attack
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// ErrSecretNotFound is returned when no guess at a shared secret is confirmed
+// by an oracle.
+var ErrSecretNotFound = errors.New("attack: shared secret not found")
+
+// ExploitSmallSubgroupConfinement recovers the residue of a Diffie-Hellman
+// private key, x, modulo the product of the distinct prime factors, less than
+// maxFactor, of j = (P-1)/Q, where Q is the prime order of the group's
+// generator (Lim and Lee).
+//
+// For each such factor r, an element h of order r is found and sent to the
+// victim as a public key. The shared secret the victim computes, h^x, is then
+// one of only r values, h^(x mod r). The oracle returns a function that
+// reports whether a guess at that shared secret is right, which is typically
+// determined by checking a MAC the victim computed with it. Trying each value
+// in turn reveals x mod r. The residues are combined with the Chinese
+// Remainder Theorem.
+//
+// When not nil, logf is used to log each residue as it is recovered.
+//
+// It returns ErrInvalidGroup if the group's Q is not given or does not divide
+// P-1.
+func ExploitSmallSubgroupConfinement(
+	group *dh.Group,
+	maxFactor int64,
+	oracle func(h *big.Int) (isSecret func(secret *big.Int) bool, err error),
+	logf func(format string, a ...any),
+) (residue, modulus *big.Int, err error) {
+	if group.Q == nil || group.Q.Sign() <= 0 {
+		return nil, nil, dh.ErrInvalidGroup
+	}
+	pMinusOne := new(big.Int).Sub(group.P, big.NewInt(1))
+	j, rem := new(big.Int).QuoRem(pMinusOne, group.Q, new(big.Int))
+	if rem.Sign() != 0 {
+		return nil, nil, dh.ErrInvalidGroup
+	}
+
+	var residues, moduli []*big.Int
+	for _, r := range smallPrimeFactors(j, maxFactor) {
+		h, err := elementOfOrder(group.P, r)
+		if err != nil {
+			return nil, nil, err
+		}
+		isSecret, err := oracle(h)
+		if err != nil {
+			return nil, nil, fmt.Errorf("querying oracle: %w", err)
+		}
+
+		// Try each secret h^i for i in [0, r).
+		found := false
+		secret := big.NewInt(1)
+		for i := int64(0); i < r.Int64(); i++ {
+			if isSecret(secret) {
+				residues = append(residues, big.NewInt(i))
+				moduli = append(moduli, r)
+				found = true
+				break
+			}
+			secret.Mul(secret, h).Mod(secret, group.P)
+		}
+		if !found {
+			return nil, nil, fmt.Errorf("recovering x mod %d: %w", r, ErrSecretNotFound)
+		}
+
+		if logf != nil {
+			logf("x = %d mod %d\n", residues[len(residues)-1], r)
+		}
+	}
+
+	return crt(residues, moduli)
+}
+
+// smallPrimeFactors returns the distinct prime factors of n that are less
+// than max, in increasing order, found by trial division.
+func smallPrimeFactors(n *big.Int, max int64) []*big.Int {
+	var factors []*big.Int
+	n = new(big.Int).Set(n)
+	rem := new(big.Int)
+	for d := int64(2); d < max; d++ {
+		bd := big.NewInt(d)
+		if rem.Rem(n, bd).Sign() != 0 {
+			continue
+		}
+		factors = append(factors, bd)
+		for rem.Rem(n, bd).Sign() == 0 {
+			n.Quo(n, bd)
+		}
+	}
+	return factors
+}
+
+// elementOfOrder returns a random element of order r in the multiplicative
+// group of integers modulo the prime p, where r is a prime factor of p-1.
+func elementOfOrder(p, r *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	e := new(big.Int).Sub(p, one)
+	e.Quo(e, r)
+	for {
+		a, err := rand.Int(rand.Reader, p)
+		if err != nil {
+			return nil, fmt.Errorf("generating random element: %w", err)
+		}
+		if a.Sign() == 0 {
+			continue
+		}
+		if h := a.Exp(a, e, p); h.Cmp(one) != 0 {
+			return h, nil
+		}
+	}
+}
+
+// RecoverDHKeyFromResidue recovers the private key x of the public key
+// y = G^x mod P in group, given n = x mod r, such as from
+// ExploitSmallSubgroupConfinement. The group's Q must be given.
+//
+// Since x = n + m*r for some m in [0, (Q-1)/r], y' = y * G^-n = (G^r)^m, and m
+// is found with DiscreteLogInRange. This takes about sqrt(Q/r) operations
+// rather than sqrt(Q).
+//
+// When not nil, logf is used to log the expected and actual number of jumps.
+func RecoverDHKeyFromResidue(
+	group *dh.Group,
+	y, residue, modulus *big.Int,
+	logf func(format string, a ...any),
+) (*big.Int, error) {
+	if group.Q == nil || group.Q.Sign() <= 0 {
+		return nil, dh.ErrInvalidGroup
+	}
+
+	gn := new(big.Int).Exp(group.G, residue, group.P)
+	gnInv, err := rsa.InvMod(gn, group.P)
+	if err != nil {
+		return nil, fmt.Errorf("inverting g^n: %w", err)
+	}
+	yPrime := new(big.Int).Mul(y, gnInv)
+	yPrime.Mod(yPrime, group.P)
+	gPrime := new(big.Int).Exp(group.G, modulus, group.P)
+
+	width := new(big.Int).Sub(group.Q, big.NewInt(1))
+	width.Quo(width, modulus)
+	k, n := KangarooParams(width)
+	if logf != nil {
+		logf("searching [0, %d] with k = %d, n = %d: expect at most ~%d jumps\n",
+			width, k, n, ExpectedKangarooJumps(width, k, n))
+	}
+
+	m, jumps, err := DiscreteLogInRange(group.P, gPrime, yPrime, big.NewInt(0), width, k, n)
+	if logf != nil {
+		logf("took %d jumps\n", jumps)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	x := m.Mul(m, modulus)
+	return x.Add(x, residue), nil
+}
@@ -0,0 +1,91 @@
+package attack
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/dh"
+)
+
+func TestSmallPrimeFactors(t *testing.T) {
+	// 2^3 * 3 * 5^2 * 7 * 1009
+	n := big.NewInt(8 * 3 * 25 * 7 * 1009)
+	got := smallPrimeFactors(n, 1000)
+	want := []int64{2, 3, 5, 7}
+	if len(got) != len(want) {
+		t.Fatalf("want: %v, got: %v", want, got)
+	}
+	for i := range want {
+		if got[i].Int64() != want[i] {
+			t.Fatalf("want: %v, got: %v", want, got)
+		}
+	}
+}
+
+func TestExploitSmallSubgroupConfinement(t *testing.T) {
+	// p - 1 = 2 * 3 * 5 * 7 * 11 * 13 * q, where q = 127 is the order of g.
+	group := &dh.Group{P: big.NewInt(3813811), G: big.NewInt(345533), Q: big.NewInt(127)}
+	if !group.P.ProbablyPrime(20) {
+		t.Fatal("test modulus is not prime")
+	}
+	x, err := rand.Int(rand.Reader, group.Q)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	oracle := func(h *big.Int) (func(*big.Int) bool, error) {
+		want := new(big.Int).Exp(h, x, group.P)
+		return func(secret *big.Int) bool { return secret.Cmp(want) == 0 }, nil
+	}
+
+	residue, modulus, err := ExploitSmallSubgroupConfinement(group, 100, oracle, nil)
+	if err != nil {
+		t.Fatalf("exploiting small subgroups: %v", err)
+	}
+	if modulus.Int64() != 2*3*5*7*11*13 {
+		t.Fatalf("want modulus: %d, got: %d", 2*3*5*7*11*13, modulus)
+	}
+	if want := new(big.Int).Mod(x, modulus); want.Cmp(residue) != 0 {
+		t.Fatalf("want: %d, got: %d", want, residue)
+	}
+}
+
+func TestDiscreteLogInRange(t *testing.T) {
+	group := dh.NISTGroup()
+	p, g := group.P, group.G
+
+	a, b := big.NewInt(1<<30), big.NewInt(1<<30+1<<24)
+	width := new(big.Int).Sub(b, a)
+	k, n := KangarooParams(width)
+
+	found := 0
+	for i := 0; i < 5; i++ {
+		want, err := rand.Int(rand.Reader, width)
+		if err != nil {
+			t.Fatalf("generating exponent: %v", err)
+		}
+		want.Add(want, a)
+		y := new(big.Int).Exp(g, want, p)
+
+		got, jumps, err := DiscreteLogInRange(p, g, y, a, b, k, n)
+		if max := 2 * ExpectedKangarooJumps(width, k, n); jumps > max {
+			t.Fatalf("want at most %d jumps, got: %d", max, jumps)
+		}
+		if errors.Is(err, ErrNoDiscreteLog) {
+			// The kangaroo algorithm fails with small probability.
+			continue
+		}
+		if err != nil {
+			t.Fatalf("finding discrete log: %v", err)
+		}
+		if want.Cmp(got) != 0 {
+			t.Fatalf("want: %d, got: %d", want, got)
+		}
+		found++
+	}
+	if found < 3 {
+		t.Fatalf("want most discrete logs found, got %d of 5", found)
+	}
+}
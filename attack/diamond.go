@@ -0,0 +1,168 @@
+package attack
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/saclark/cryptopals/md"
+)
+
+// DiamondStructure is a binary tree of block collisions for a Merkle-Damgård
+// hash which funnels any of its 2^k leaf states into a single root state in k
+// blocks.
+type DiamondStructure struct {
+	// States[0] holds the 2^k leaf states and States[k] the root state. From
+	// States[i][j], the block Blocks[i][j] leads to States[i+1][j/2].
+	States [][][]byte
+	// Blocks are the blocks leading from each state to the next level.
+	Blocks [][][]byte
+}
+
+// BuildDiamondStructure builds a diamond structure of depth k for h (Kelsey
+// and Kohno). Each level is built by pairing up the states of the level below
+// and finding a block collision from each pair of states into a single state,
+// halving the number of states. The pairs of each level are divided among
+// workers goroutines.
+//
+// The leaf states are the first 2^k integers, big-endian encoded to the size of
+// the hash's state.
+//
+// When not nil, logf is used to log the attack's progress.
+//
+// It panics if k is less than 0, if the state is too small to hold 2^k
+// distinct leaves, or if workers is less than 1.
+func BuildDiamondStructure(h *md.Hash, k, workers int, logf func(format string, a ...any)) *DiamondStructure {
+	if k < 0 || k >= 8*h.Size() || k >= 63 {
+		panic("k out of range")
+	}
+	if workers < 1 {
+		panic("workers not > 0")
+	}
+
+	leaves := make([][]byte, 1<<k)
+	for i := range leaves {
+		leaves[i] = counterBlock(h.Size(), uint64(i))
+	}
+
+	d := &DiamondStructure{States: [][][]byte{leaves}}
+	for level := 0; level < k; level++ {
+		states := d.States[level]
+		blocks := make([][]byte, len(states))
+		next := make([][]byte, len(states)/2)
+
+		pairs := make(chan int)
+		var wg sync.WaitGroup
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range pairs {
+					blocks[2*j], blocks[2*j+1], next[j] = findCrossCollision(h, states[2*j], states[2*j+1])
+				}
+			}()
+		}
+		for j := range next {
+			pairs <- j
+		}
+		close(pairs)
+		wg.Wait()
+
+		d.Blocks = append(d.Blocks, blocks)
+		d.States = append(d.States, next)
+		if logf != nil {
+			logf("level %d: %d states\n", level+1, len(next))
+		}
+	}
+
+	return d
+}
+
+// LoadDiamondStructure reads a diamond structure written by Save.
+func LoadDiamondStructure(r io.Reader) (*DiamondStructure, error) {
+	var d DiamondStructure
+	if err := gob.NewDecoder(r).Decode(&d); err != nil {
+		return nil, fmt.Errorf("decoding diamond structure: %w", err)
+	}
+	if err := d.validate(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Save writes d to w, so that it can later be read by LoadDiamondStructure.
+func (d *DiamondStructure) Save(w io.Writer) error {
+	if err := gob.NewEncoder(w).Encode(d); err != nil {
+		return fmt.Errorf("encoding diamond structure: %w", err)
+	}
+	return nil
+}
+
+// K returns k, the depth of the diamond structure.
+func (d *DiamondStructure) K() int {
+	return len(d.Blocks)
+}
+
+// Root returns the state that every leaf state is funnelled into.
+func (d *DiamondStructure) Root() []byte {
+	return d.States[len(d.States)-1][0]
+}
+
+// Digest returns the digest, under h, of every message herded into d by Herd
+// with a prefix of prefixBlocks blocks. This is what is committed to in
+// advance.
+func (d *DiamondStructure) Digest(h *md.Hash, prefixBlocks int) []byte {
+	n := prefixBlocks + 1 + d.K()
+	return h.SumFromState(d.Root(), uint64(n*h.BlockSize), nil)
+}
+
+// Herd returns a message beginning with prefix, zero padded to a whole number
+// of blocks, whose hash under h is the digest of d for that many prefix blocks.
+//
+// It searches for a linking block from the state after the prefix to any of the
+// 2^k leaf states, and then follows the path from that leaf to the root, so
+// finding the link takes about 2^k times less work than finding a preimage.
+func (d *DiamondStructure) Herd(h *md.Hash, prefix []byte) []byte {
+	message := append([]byte(nil), prefix...)
+	if r := len(message) % h.BlockSize; r != 0 {
+		message = append(message, make([]byte, h.BlockSize-r)...)
+	}
+
+	leaves := make(map[string]int, len(d.States[0]))
+	for i, leaf := range d.States[0] {
+		leaves[string(leaf)] = i
+	}
+
+	state := h.Iterate(h.IV, message)
+	for i := uint64(0); ; i++ {
+		link := counterBlock(h.BlockSize, i)
+		j, ok := leaves[string(h.Compress(state, link))]
+		if !ok {
+			continue
+		}
+		message = append(message, link...)
+		for level := 0; level < d.K(); level, j = level+1, j/2 {
+			message = append(message, d.Blocks[level][j]...)
+		}
+		return message
+	}
+}
+
+// validate checks that the shape of d is that of a diamond structure.
+func (d *DiamondStructure) validate() error {
+	k := len(d.Blocks)
+	if len(d.States) != k+1 || k >= 63 {
+		return errors.New("attack: invalid diamond structure depth")
+	}
+	for level := 0; level <= k; level++ {
+		if len(d.States[level]) != 1<<(k-level) {
+			return fmt.Errorf("attack: invalid diamond structure level %d", level)
+		}
+		if level < k && len(d.Blocks[level]) != len(d.States[level]) {
+			return fmt.Errorf("attack: invalid diamond structure level %d", level)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package attack
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/saclark/cryptopals/md"
+)
+
+func TestDiamondStructure_Herd(t *testing.T) {
+	h := md.NewAESHash([]byte{0xAB, 0xCD})
+	k := 5
+	d := BuildDiamondStructure(h, k, 4, nil)
+
+	for level := 0; level < k; level++ {
+		for j, state := range d.States[level] {
+			if got := h.Compress(state, d.Blocks[level][j]); !bytes.Equal(d.States[level+1][j/2], got) {
+				t.Fatalf("level %d, state %d: want: '%x', got: '%x'", level, j, d.States[level+1][j/2], got)
+			}
+		}
+	}
+
+	for _, prefix := range []string{"", "Never gonna give you up", "Never gonna let you down, never gonna run around"} {
+		message := d.Herd(h, []byte(prefix))
+		if !bytes.HasPrefix(message, []byte(prefix)) {
+			t.Fatalf("want prefix: '%s', got message: '%x'", prefix, message)
+		}
+		prefixBlocks := (len(prefix) + h.BlockSize - 1) / h.BlockSize
+		if want, got := d.Digest(h, prefixBlocks), h.Sum(message); !bytes.Equal(want, got) {
+			t.Fatalf("prefix '%s': want: '%x', got: '%x'", prefix, want, got)
+		}
+	}
+}
+
+func TestDiamondStructure_SaveLoad(t *testing.T) {
+	h := md.NewAESHash([]byte{0xAB, 0xCD})
+	d := BuildDiamondStructure(h, 3, 1, nil)
+
+	var buf bytes.Buffer
+	if err := d.Save(&buf); err != nil {
+		t.Fatalf("saving: %v", err)
+	}
+	loaded, err := LoadDiamondStructure(&buf)
+	if err != nil {
+		t.Fatalf("loading: %v", err)
+	}
+	if loaded.K() != d.K() || !bytes.Equal(loaded.Root(), d.Root()) {
+		t.Fatalf("want k = %d, root '%x', got k = %d, root '%x'", d.K(), d.Root(), loaded.K(), loaded.Root())
+	}
+
+	if _, err := LoadDiamondStructure(bytes.NewReader([]byte("garbage"))); err == nil {
+		t.Fatal("want error for invalid input, got nil")
+	}
+}
@@ -0,0 +1,212 @@
+package attack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/saclark/cryptopals/dsa"
+	"github.com/saclark/cryptopals/rsa"
+	"github.com/saclark/cryptopals/sha1"
+)
+
+var ErrDSAKeyNotFound = errors.New("attack: DSA private key not found")
+
+// dsaNonceCheckInterval is how many nonces a worker tries between checks for
+// cancellation.
+const dsaNonceCheckInterval = 1 << 10
+
+// RecoverDSAKeyFromBoundedNonce recovers the DSA private key that produced the
+// signature (r, s) of message, given that the nonce, k, used to produce it is
+// in [kMin, kMax]. Candidate keys are identified by comparing their
+// dsa.Fingerprint to fingerprint.
+//
+// Since s = k^-1 (H(m) + x*r) mod q, each possible k gives us a candidate
+// x = (s*k - H(m)) * r^-1 mod q. The range of nonces is split across workers
+// goroutines.
+//
+// ErrDSAKeyNotFound is returned if no nonce in the range produces the key.
+// When not nil, logf is used to log the attack's progress.
+//
+// It panics if workers is less than 1 or if kMin is greater than kMax.
+func RecoverDSAKeyFromBoundedNonce(
+	ctx context.Context,
+	params *dsa.Parameters,
+	message []byte,
+	r, s *big.Int,
+	kMin, kMax uint64,
+	fingerprint sha1.Digest,
+	workers int,
+	logf func(format string, a ...any),
+) (*dsa.PrivateKey, error) {
+	switch {
+	case workers < 1:
+		panic("workers not > 0")
+	case kMin > kMax:
+		panic("kMin > kMax")
+	}
+
+	rInv, err := rsa.InvMod(r, params.Q)
+	if err != nil {
+		return nil, ErrDSAKeyNotFound
+	}
+
+	// Incrementing k by one increments x by s * r^-1.
+	xStep := new(big.Int).Mul(s, rInv)
+	xStep.Mod(xStep, params.Q)
+	h := params.Hash(message)
+
+	if logf != nil {
+		logf("searching for nonce in [%d, %d] with %d workers\n", kMin, kMax, workers)
+	}
+
+	searchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	found := make(chan *big.Int, 1)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		start := kMin + uint64(i)
+		if start > kMax || start < kMin {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			x := dsaPrivateKeyFromNonce(params.Q, h, s, rInv, new(big.Int).SetUint64(start))
+			step := new(big.Int).Mul(xStep, big.NewInt(int64(workers)))
+			for k, n := start, 0; ; n++ {
+				if n%dsaNonceCheckInterval == 0 && searchCtx.Err() != nil {
+					return
+				}
+				if dsa.Fingerprint(x) == fingerprint {
+					if logf != nil {
+						logf("found nonce: %d\n", k)
+					}
+					select {
+					case found <- x:
+					default:
+					}
+					cancel()
+					return
+				}
+				if kMax-k < uint64(workers) {
+					return
+				}
+				k += uint64(workers)
+				x.Add(x, step).Mod(x, params.Q)
+			}
+		}()
+	}
+	wg.Wait()
+
+	select {
+	case x := <-found:
+		return dsa.NewPrivateKey(params, x), nil
+	default:
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrDSAKeyNotFound
+	}
+}
+
+// DSASignedMessage is a message along with its DSA signature, (R, S).
+type DSASignedMessage struct {
+	Message []byte
+	R       *big.Int
+	S       *big.Int
+}
+
+// RecoverDSAKeyFromRepeatedNonce recovers the DSA private key for pub from a
+// collection of messages it signed, some of which were signed with the same
+// nonce.
+//
+// Since r depends only on the nonce, signatures sharing an r share a nonce.
+// For two such signatures, s1 - s2 = k^-1 (H(m1) - H(m2)) mod q, and so
+// k = (H(m1) - H(m2)) / (s1 - s2) mod q, from which we can recover x. Each
+// candidate x is validated against the public key, Y.
+//
+// ErrDSAKeyNotFound is returned if no pair of signatures yields the key.
+func RecoverDSAKeyFromRepeatedNonce(pub *dsa.PublicKey, signed []DSASignedMessage) (*dsa.PrivateKey, error) {
+	byR := make(map[string][]DSASignedMessage)
+	for _, m := range signed {
+		key := m.R.String()
+		byR[key] = append(byR[key], m)
+	}
+
+	for _, group := range byR {
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				x, ok := dsaPrivateKeyFromSharedNonce(&pub.Parameters, group[i], group[j])
+				if !ok {
+					continue
+				}
+				if new(big.Int).Exp(pub.G, x, pub.P).Cmp(pub.Y) == 0 {
+					return dsa.NewPrivateKey(&pub.Parameters, x), nil
+				}
+			}
+		}
+	}
+
+	return nil, ErrDSAKeyNotFound
+}
+
+// dsaPrivateKeyFromSharedNonce returns the private key that produced a and b,
+// assuming they were signed with the same nonce. It returns false if a nonce
+// cannot be derived from the pair.
+func dsaPrivateKeyFromSharedNonce(params *dsa.Parameters, a, b DSASignedMessage) (*big.Int, bool) {
+	sDiff := new(big.Int).Sub(a.S, b.S)
+	sDiff.Mod(sDiff, params.Q)
+	sDiffInv, err := rsa.InvMod(sDiff, params.Q)
+	if err != nil {
+		return nil, false
+	}
+	rInv, err := rsa.InvMod(a.R, params.Q)
+	if err != nil {
+		return nil, false
+	}
+
+	ha := params.Hash(a.Message)
+	k := new(big.Int).Sub(ha, params.Hash(b.Message))
+	k.Mul(k, sDiffInv)
+	k.Mod(k, params.Q)
+
+	return dsaPrivateKeyFromNonce(params.Q, ha, a.S, rInv, k), true
+}
+
+// dsaPrivateKeyFromNonce returns x = (s*k - H(m)) * r^-1 mod q, the private key
+// which, using the nonce k, produces a signature with the given s.
+func dsaPrivateKeyFromNonce(q, h, s, rInv, k *big.Int) *big.Int {
+	x := new(big.Int).Mul(s, k)
+	x.Sub(x, h)
+	x.Mul(x, rInv)
+	return x.Mod(x, q)
+}
+
+// ForgeDSAMagicSignature forges a signature which verifies for any message
+// under pub, given that pub's domain parameters have been tampered with such
+// that G ≡ 1 (mod P), for example G = P+1. z may be any integer that is
+// invertible mod Q.
+//
+// With G ≡ 1, g^u1 = 1 and verification reduces to checking that
+// r = (y^(r*w) mod p) mod q, where w = s^-1. Choosing r = (y^z mod p) mod q and
+// s = r/z mod q makes r*w = z, satisfying the check regardless of the message.
+func ForgeDSAMagicSignature(pub *dsa.PublicKey, z *big.Int) (r, s *big.Int, err error) {
+	if new(big.Int).Mod(pub.G, pub.P).Cmp(big.NewInt(1)) != 0 {
+		return nil, nil, errors.New("attack: generator not congruent to 1 mod p")
+	}
+	zInv, err := rsa.InvMod(z, pub.Q)
+	if err != nil {
+		return nil, nil, fmt.Errorf("attack: z not invertible mod q: %w", err)
+	}
+
+	r = new(big.Int).Exp(pub.Y, z, pub.P)
+	r.Mod(r, pub.Q)
+	s = new(big.Int).Mul(r, zInv)
+	s.Mod(s, pub.Q)
+
+	return r, s, nil
+}
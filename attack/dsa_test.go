@@ -0,0 +1,136 @@
+package attack
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/dsa"
+	"github.com/saclark/cryptopals/sha1"
+)
+
+func TestRecoverDSAKeyFromBoundedNonce(t *testing.T) {
+	params := dsa.CryptopalsParameters()
+	priv, err := dsa.GenerateKey(params, rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	message := []byte("hello, world")
+	r, s, err := dsa.SignWithNonce(priv, big.NewInt(31337), message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	fingerprint := dsa.Fingerprint(priv.X)
+
+	t.Run("odd length hex key", func(t *testing.T) {
+		// The fingerprint of a key whose hex encoding has an odd number of
+		// digits is taken without a leading zero.
+		priv := dsa.NewPrivateKey(params, new(big.Int).Lsh(big.NewInt(0xabc), 96))
+		r, s, err := dsa.SignWithNonce(priv, big.NewInt(31337), message)
+		if err != nil {
+			t.Fatalf("signing: %v", err)
+		}
+		fingerprint := sha1.Sum([]byte(priv.X.Text(16)))
+		if n := len(priv.X.Text(16)); n%2 != 1 {
+			t.Fatalf("want odd length hex key, got length %d", n)
+		}
+
+		got, err := RecoverDSAKeyFromBoundedNonce(context.Background(), params, message, r, s, 30000, 40000, fingerprint, 3, nil)
+		if err != nil {
+			t.Fatalf("recovering key: %v", err)
+		}
+		if priv.X.Cmp(got.X) != 0 {
+			t.Fatalf("want: %x, got: %x", priv.X, got.X)
+		}
+	})
+
+	t.Run("in range", func(t *testing.T) {
+		got, err := RecoverDSAKeyFromBoundedNonce(context.Background(), params, message, r, s, 30000, 40000, fingerprint, 3, nil)
+		if err != nil {
+			t.Fatalf("recovering key: %v", err)
+		}
+		if priv.X.Cmp(got.X) != 0 {
+			t.Fatalf("want: %x, got: %x", priv.X, got.X)
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		_, err := RecoverDSAKeyFromBoundedNonce(context.Background(), params, message, r, s, 0, 30000, fingerprint, 3, nil)
+		if !errors.Is(err, ErrDSAKeyNotFound) {
+			t.Fatalf("want: %v, got: %v", ErrDSAKeyNotFound, err)
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := RecoverDSAKeyFromBoundedNonce(ctx, params, message, r, s, 0, 1<<20, fingerprint, 3, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("want: %v, got: %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestRecoverDSAKeyFromRepeatedNonce(t *testing.T) {
+	params := dsa.CryptopalsParameters()
+	priv, err := dsa.GenerateKey(params, rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	var signed []DSASignedMessage
+	for i, k := range []int64{101, 202, 303, 202, 404} {
+		message := []byte{byte('a' + i)}
+		r, s, err := dsa.SignWithNonce(priv, big.NewInt(k), message)
+		if err != nil {
+			t.Fatalf("signing: %v", err)
+		}
+		signed = append(signed, DSASignedMessage{Message: message, R: r, S: s})
+	}
+
+	got, err := RecoverDSAKeyFromRepeatedNonce(&priv.PublicKey, signed)
+	if err != nil {
+		t.Fatalf("recovering key: %v", err)
+	}
+	if priv.X.Cmp(got.X) != 0 {
+		t.Fatalf("want: %x, got: %x", priv.X, got.X)
+	}
+
+	// Without the repeated nonce, the key cannot be recovered.
+	signed = append(signed[:3], signed[4:]...)
+	if _, err := RecoverDSAKeyFromRepeatedNonce(&priv.PublicKey, signed); !errors.Is(err, ErrDSAKeyNotFound) {
+		t.Fatalf("want: %v, got: %v", ErrDSAKeyNotFound, err)
+	}
+}
+
+func TestForgeDSAMagicSignature(t *testing.T) {
+	params := dsa.CryptopalsParameters()
+	params.G = new(big.Int).Add(params.P, big.NewInt(1))
+	priv, err := dsa.GenerateKey(params, rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	r, s, err := ForgeDSAMagicSignature(&priv.PublicKey, big.NewInt(42))
+	if err != nil {
+		t.Fatalf("forging signature: %v", err)
+	}
+
+	for _, message := range []string{"Hello, world", "Goodbye, world"} {
+		if err := dsa.Verify(&priv.PublicKey, []byte(message), r, s); err != nil {
+			t.Fatalf("verifying '%s': %v", message, err)
+		}
+	}
+}
+
+func TestForgeDSAMagicSignature_UntamperedGenerator(t *testing.T) {
+	priv, err := dsa.GenerateKey(dsa.CryptopalsParameters(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	if _, _, err := ForgeDSAMagicSignature(&priv.PublicKey, big.NewInt(42)); err == nil {
+		t.Fatal("want error, got nil")
+	}
+}
@@ -0,0 +1,208 @@
+package attack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/saclark/cryptopals/md"
+)
+
+// Multicollision is a set of 2^n messages of n blocks each that all leave a
+// Merkle-Damgård hash in the same state. Each message is formed by choosing one
+// of the two blocks in each of the n pairs of Blocks, in order.
+type Multicollision struct {
+	// Blocks are the colliding pairs of blocks.
+	Blocks [][2][]byte
+	// State is the state of the hash after compressing any of the messages.
+	State []byte
+}
+
+// Message returns the i-th of the 2^n colliding messages, where bit j of i
+// selects the block chosen from the j-th pair.
+func (m *Multicollision) Message(i uint64) []byte {
+	var message []byte
+	for j, pair := range m.Blocks {
+		message = append(message, pair[(i>>j)&1]...)
+	}
+	return message
+}
+
+// FindBlockCollision searches for two distinct blocks that both compress state
+// to the same next state, and returns them along with that state.
+//
+// It is a birthday attack, requiring around 2^(b/2) calls to the compression
+// function for a b-bit state, so it is only practical for weak hashes.
+func FindBlockCollision(h *md.Hash, state []byte) (b0, b1, next []byte) {
+	seen := make(map[string]uint64)
+	for i := uint64(0); ; i++ {
+		block := counterBlock(h.BlockSize, i)
+		next := h.Compress(state, block)
+		if j, ok := seen[string(next)]; ok {
+			return counterBlock(h.BlockSize, j), block, next
+		}
+		seen[string(next)] = i
+	}
+}
+
+// FindMulticollision generates 2^n messages which all leave h in the same
+// state when starting from state, by finding n block collisions in a row, each
+// starting from the state the previous one left off at (Joux's multicollision
+// attack). This requires only n times the work of finding a single collision.
+func FindMulticollision(h *md.Hash, state []byte, n int) *Multicollision {
+	m := &Multicollision{State: append([]byte(nil), state...)}
+	for i := 0; i < n; i++ {
+		m.Extend(h)
+	}
+	return m
+}
+
+// Extend doubles the number of colliding messages in m by finding one more
+// block collision, starting from m.State.
+func (m *Multicollision) Extend(h *md.Hash) {
+	b0, b1, next := FindBlockCollision(h, m.State)
+	m.Blocks = append(m.Blocks, [2][]byte{b0, b1})
+	m.State = next
+}
+
+// counterBlock returns a block whose leading bytes are the big-endian encoding
+// of i, for generating distinct blocks deterministically.
+func counterBlock(blockSize int, i uint64) []byte {
+	var ctr [8]byte
+	binary.BigEndian.PutUint64(ctr[:], i)
+	block := make([]byte, blockSize)
+	if blockSize >= len(ctr) {
+		copy(block, ctr[:])
+	} else {
+		copy(block, ctr[len(ctr)-blockSize:])
+	}
+	return block
+}
+
+// ExpandableMessage is a (k, k+2^k-1)-expandable message: a set of messages,
+// one of every length from k to k+2^k-1 blocks, that all leave a Merkle-Damgård
+// hash in the same state.
+//
+// It is made of k collisions between a short, single-block message and a long
+// message of 2^(k-1-i)+1 blocks, for i in [0, k). Each message is formed by
+// choosing the short or long message from each collision, in order, and the
+// binary representation of its length minus k says which.
+type ExpandableMessage struct {
+	// Short are the single-block messages.
+	Short [][]byte
+	// Long are the longer messages colliding with the corresponding Short.
+	Long [][]byte
+	// State is the state of the hash after compressing any of the messages.
+	State []byte
+}
+
+// FindExpandableMessage builds a (k, k+2^k-1)-expandable message for h,
+// starting from state (Kelsey and Schneier). This requires only k times the
+// work of finding a single collision.
+func FindExpandableMessage(h *md.Hash, state []byte, k int) *ExpandableMessage {
+	e := &ExpandableMessage{State: append([]byte(nil), state...)}
+	dummy := make([]byte, h.BlockSize)
+	for i := 0; i < k; i++ {
+		// Compress the 2^(k-1-i) dummy blocks that begin the long message, then
+		// find a final block for it that collides with a single block.
+		prefix := bytes.Repeat(dummy, 1<<(k-1-i))
+		prefixState := h.Iterate(e.State, prefix)
+		short, last, next := findCrossCollision(h, e.State, prefixState)
+		e.Short = append(e.Short, short)
+		e.Long = append(e.Long, append(prefix, last...))
+		e.State = next
+	}
+	return e
+}
+
+// K returns k, the number of collisions in e.
+func (e *ExpandableMessage) K() int {
+	return len(e.Short)
+}
+
+// Message returns the message of the given number of blocks. It panics if
+// blocks is not in [k, k+2^k-1].
+func (e *ExpandableMessage) Message(blocks int) []byte {
+	k := e.K()
+	if blocks < k || blocks-k >= 1<<k {
+		panic(fmt.Sprintf("expandable message: %d blocks not in [%d, %d]", blocks, k, k+(1<<k)-1))
+	}
+
+	// Choosing the long message from the i-th collision adds 2^(k-1-i) blocks.
+	extra := blocks - k
+	var message []byte
+	for i := 0; i < k; i++ {
+		if extra&(1<<(k-1-i)) != 0 {
+			message = append(message, e.Long[i]...)
+		} else {
+			message = append(message, e.Short[i]...)
+		}
+	}
+	return message
+}
+
+// FindSecondPreimage finds a different message with the same length and hash
+// under h as target, which must be at least k+1 blocks long, using a
+// (k, k+2^k-1)-expandable message.
+//
+// Any message that reaches one of the intermediate states of the hash of
+// target, after the same number of blocks, can be completed with the rest of
+// target. We search for a bridge block from the final state of the expandable
+// message to any of the 2^k or so intermediate states, which takes about 2^k
+// times less work than a brute force second preimage, and then expand the
+// message to the right length.
+func FindSecondPreimage(h *md.Hash, target []byte, k int) ([]byte, error) {
+	n := len(target) / h.BlockSize
+	if n < k+1 {
+		return nil, fmt.Errorf("attack: target of %d blocks too short for k = %d", n, k)
+	}
+
+	// intermediate maps a state to the number of blocks of target after which
+	// the state is reached, for those that can be bridged to.
+	intermediate := make(map[string]int)
+	state := h.IV
+	for i := 1; i <= n; i++ {
+		state = h.Compress(state, target[(i-1)*h.BlockSize:i*h.BlockSize])
+		if i-1 < k || i-1-k >= 1<<k {
+			continue
+		}
+		if _, ok := intermediate[string(state)]; !ok {
+			intermediate[string(state)] = i
+		}
+	}
+
+	e := FindExpandableMessage(h, h.IV, k)
+	for j := uint64(0); ; j++ {
+		bridge := counterBlock(h.BlockSize, j)
+		i, ok := intermediate[string(h.Compress(e.State, bridge))]
+		if !ok {
+			continue
+		}
+		preimage := append(e.Message(i-1), bridge...)
+		return append(preimage, target[i*h.BlockSize:]...), nil
+	}
+}
+
+// findCrossCollision searches for a block b1 from state s1 and a block b2 from
+// state s2 that compress to the same next state, and returns them along with
+// that state.
+func findCrossCollision(h *md.Hash, s1, s2 []byte) (b1, b2, next []byte) {
+	seen1 := make(map[string]uint64)
+	seen2 := make(map[string]uint64)
+	for i := uint64(0); ; i++ {
+		block := counterBlock(h.BlockSize, i)
+		next1 := h.Compress(s1, block)
+		if j, ok := seen2[string(next1)]; ok {
+			return block, counterBlock(h.BlockSize, j), next1
+		}
+		next2 := h.Compress(s2, block)
+		if bytes.Equal(next1, next2) {
+			return block, block, next1
+		}
+		if j, ok := seen1[string(next2)]; ok {
+			return counterBlock(h.BlockSize, j), block, next2
+		}
+		seen1[string(next1)] = i
+		seen2[string(next2)] = i
+	}
+}
@@ -0,0 +1,262 @@
+package attack
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/saclark/cryptopals/md4"
+)
+
+// The kinds of sufficient condition Wang et al. place on a bit of a chaining
+// variable.
+const (
+	bitZero        = iota // The bit is 0.
+	bitOne                // The bit is 1.
+	bitEqual              // The bit equals that of the previous variable.
+	bitEqualSecond        // The bit equals that of the variable before that.
+	bitNotEqual           // The bit differs from that of the previous variable.
+)
+
+// bitCondition is a sufficient condition on the bit numbered bit, counting
+// from 1 at the least significant bit as in the paper.
+type bitCondition struct {
+	bit  int
+	kind int
+}
+
+// The chaining variables of the first 32 steps of the MD4 compression function
+// are numbered in the order they are computed: q[0] to q[3] are the initial a,
+// d, c and b, and step i computes q[i+4] from q[i], ..., q[i+3]. So a1, d1, c1
+// and b1 are q[4] to q[7], a5 is q[20], and so on.
+const (
+	wangStates = 4 + 16 + 16
+	wangA5     = 20
+	wangD5     = 21
+)
+
+// wangConditions are the sufficient conditions, from "Cryptanalysis of the
+// Hash Functions MD4 and RIPEMD" (Wang et al., 2005), on the chaining
+// variables of the first two rounds for the differential to hold.
+var wangConditions = [wangStates][]bitCondition{
+	// Round 1.
+	4:  {{7, bitEqual}},
+	5:  {{7, bitZero}, {8, bitEqual}, {11, bitEqual}},
+	6:  {{7, bitOne}, {8, bitOne}, {11, bitZero}, {26, bitEqual}},
+	7:  {{7, bitOne}, {8, bitZero}, {11, bitZero}, {26, bitZero}},
+	8:  {{8, bitOne}, {11, bitOne}, {26, bitZero}, {14, bitEqual}},
+	9:  {{14, bitZero}, {19, bitEqual}, {20, bitEqual}, {21, bitEqual}, {22, bitEqual}, {26, bitOne}},
+	10: {{13, bitEqual}, {14, bitZero}, {15, bitEqual}, {19, bitZero}, {20, bitZero}, {21, bitOne}, {22, bitZero}},
+	11: {{13, bitOne}, {14, bitOne}, {15, bitZero}, {17, bitEqual}, {19, bitZero}, {20, bitZero}, {21, bitZero}, {22, bitZero}},
+	12: {{13, bitOne}, {14, bitOne}, {15, bitOne}, {17, bitZero}, {19, bitZero}, {20, bitZero}, {21, bitZero}, {23, bitEqual}, {22, bitOne}, {26, bitEqual}},
+	13: {{13, bitOne}, {14, bitOne}, {15, bitOne}, {17, bitZero}, {20, bitZero}, {21, bitOne}, {22, bitOne}, {23, bitZero}, {26, bitOne}, {30, bitEqual}},
+	14: {{17, bitOne}, {20, bitZero}, {21, bitZero}, {22, bitZero}, {23, bitZero}, {26, bitZero}, {30, bitOne}, {32, bitEqual}},
+	15: {{20, bitZero}, {21, bitOne}, {22, bitOne}, {23, bitEqual}, {26, bitOne}, {30, bitZero}, {32, bitZero}},
+	16: {{23, bitZero}, {26, bitZero}, {27, bitEqual}, {29, bitEqual}, {30, bitOne}, {32, bitZero}},
+	17: {{23, bitZero}, {26, bitZero}, {27, bitOne}, {29, bitOne}, {30, bitZero}, {32, bitOne}},
+	18: {{19, bitEqual}, {23, bitOne}, {26, bitOne}, {27, bitZero}, {29, bitZero}, {30, bitZero}},
+	19: {{19, bitZero}, {26, bitEqual}, {27, bitOne}, {29, bitOne}, {30, bitZero}},
+	// Round 2.
+	20: {{19, bitEqualSecond}, {26, bitOne}, {27, bitZero}, {29, bitOne}, {32, bitOne}},
+	21: {{19, bitEqual}, {26, bitEqualSecond}, {27, bitEqualSecond}, {29, bitEqualSecond}, {32, bitEqualSecond}},
+	22: {{26, bitEqual}, {27, bitEqual}, {29, bitEqual}, {30, bitEqual}, {32, bitEqual}},
+	23: {{29, bitEqual}, {30, bitOne}, {32, bitZero}},
+	24: {{29, bitOne}, {32, bitOne}},
+	25: {{29, bitEqualSecond}},
+	26: {{29, bitEqual}, {30, bitNotEqual}, {32, bitNotEqual}},
+}
+
+var (
+	md4Round1Shifts = [4]int{3, 7, 11, 19}
+	md4Round2Shifts = [4]int{3, 5, 9, 13}
+	md4Round2Order  = [16]int{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+)
+
+// FindMD4Collision finds two distinct single-block messages with the same MD4
+// hash using Wang et al.'s differential attack.
+//
+// Random messages are modified to satisfy all of the sufficient conditions of
+// round 1 and some of round 2 by ModifyMD4Message, and paired with the message
+// differing by the differential by MD4CollisionPartner, until the pair
+// collides. Any common suffix can be appended to the messages without
+// breaking the collision.
+//
+// When not nil, logf is used to log the number of messages tried.
+func FindMD4Collision(logf func(format string, a ...any)) (m1, m2 []byte, err error) {
+	iv := md4.InitialState()
+	state := make([]byte, md4.Size)
+	for i, r := range iv {
+		binary.LittleEndian.PutUint32(state[i*4:], r)
+	}
+
+	block := make([]byte, md4.BlockSize)
+	for tries := 1; ; tries++ {
+		if _, err := rand.Read(block); err != nil {
+			return nil, nil, fmt.Errorf("generating random message: %w", err)
+		}
+		m1 = ModifyMD4Message(block)
+		m2 = MD4CollisionPartner(m1)
+		h1, h2 := md4.Compress(state, m1), md4.Compress(state, m2)
+		if string(h1) == string(h2) {
+			if logf != nil {
+				logf("collision found after %d messages\n", tries)
+			}
+			return m1, m2, nil
+		}
+		if logf != nil && tries%(1<<16) == 0 {
+			logf("%d messages tried\n", tries)
+		}
+	}
+}
+
+// MD4CollisionPartner returns the message that differs from block, a single
+// MD4 block, by Wang et al.'s differential: m1 + 2^31, m2 + 2^31 - 2^28 and
+// m12 - 2^16. If block satisfies the sufficient conditions, the two collide.
+func MD4CollisionPartner(block []byte) []byte {
+	m := md4BlockWords(block)
+	m[1] += 1 << 31
+	m[2] += 1<<31 - 1<<28
+	m[12] -= 1 << 16
+	return md4WordsBlock(m)
+}
+
+// ModifyMD4Message returns block, a single MD4 block, modified so that hashing
+// it satisfies Wang et al.'s round 1 sufficient conditions and, where it can
+// without breaking them, the conditions on a5 and d5 in round 2.
+//
+// Round 1 conditions are met by single-message modification: each chaining
+// variable is computed, its bits corrected, and the message word that produced
+// it solved for. Round 2 conditions are met by multi-message modification:
+// correcting a5 or d5 changes a message word, so the round 1 variable that it
+// also produces is changed to match, and the following message words solved
+// for so that the rest of round 1 is unaffected.
+func ModifyMD4Message(block []byte) []byte {
+	m := md4BlockWords(block)
+
+	// Single-message modification.
+	var q [wangStates]uint32
+	iv := md4.InitialState()
+	q[0], q[1], q[2], q[3] = iv[0], iv[3], iv[2], iv[1]
+	for i := 0; i < 16; i++ {
+		q[i+4] = md4Round1Step(&q, i, m[i])
+		q[i+4] = satisfyConditions(&q, i+4)
+		m[i] = md4Round1Word(&q, i)
+	}
+
+	// Multi-message modification of a5, which m0 produces along with a1.
+	modifyRound2Variable(&m, wangA5, 0)
+	// Multi-message modification of d5, which m4 produces along with a2.
+	modifyRound2Variable(&m, wangD5, 4)
+
+	return md4WordsBlock(m)
+}
+
+// modifyRound2Variable corrects the round 2 chaining variable q[v], produced
+// from message word m[w], by solving for the value of m[w] that produces the
+// corrected q[v]. The round 1 variable that m[w] also produces changes
+// accordingly, and the next four message words are solved for so that the
+// rest of round 1 is unchanged. If that breaks any round 1 condition, the
+// message is left as it was.
+func modifyRound2Variable(m *[16]uint32, v, w int) {
+	q := md4States(m)
+	if conditionsSatisfied(&q, v, v) {
+		return
+	}
+
+	orig := *m
+	j := v - 20
+	want := satisfyConditions(&q, v)
+	s := md4Round2Shifts[j%4]
+	m[w] = bits.RotateLeft32(want, -s) -
+		bits.RotateLeft32(md4.GG(q[j+16], q[j+19], q[j+18], q[j+17], 0, s), -s)
+
+	q[w+4] = md4Round1Step(&q, w, m[w])
+	for i := w + 1; i <= w+4 && i < 16; i++ {
+		m[i] = md4Round1Word(&q, i)
+	}
+
+	q = md4States(m)
+	if !conditionsSatisfied(&q, 4, 19) {
+		*m = orig
+	}
+}
+
+// md4States returns the chaining variables of the first two rounds of the MD4
+// compression of the message words m.
+func md4States(m *[16]uint32) [wangStates]uint32 {
+	var q [wangStates]uint32
+	iv := md4.InitialState()
+	q[0], q[1], q[2], q[3] = iv[0], iv[3], iv[2], iv[1]
+	for i := 0; i < 16; i++ {
+		q[i+4] = md4Round1Step(&q, i, m[i])
+	}
+	for j := 0; j < 16; j++ {
+		q[j+20] = md4.GG(q[j+16], q[j+19], q[j+18], q[j+17], m[md4Round2Order[j]], md4Round2Shifts[j%4])
+	}
+	return q
+}
+
+// md4Round1Step returns the chaining variable computed by round 1 step i from
+// message word x.
+func md4Round1Step(q *[wangStates]uint32, i int, x uint32) uint32 {
+	return md4.FF(q[i], q[i+3], q[i+2], q[i+1], x, md4Round1Shifts[i%4])
+}
+
+// md4Round1Word solves for the message word that makes round 1 step i produce
+// q[i+4].
+func md4Round1Word(q *[wangStates]uint32, i int) uint32 {
+	s := md4Round1Shifts[i%4]
+	return bits.RotateLeft32(q[i+4], -s) - bits.RotateLeft32(md4.FF(q[i], q[i+3], q[i+2], q[i+1], 0, s), -s)
+}
+
+// satisfyConditions returns q[i] with its bits set to satisfy its conditions.
+func satisfyConditions(q *[wangStates]uint32, i int) uint32 {
+	v := q[i]
+	for _, c := range wangConditions[i] {
+		mask := uint32(1) << (c.bit - 1)
+		switch c.kind {
+		case bitZero:
+			v &^= mask
+		case bitOne:
+			v |= mask
+		case bitEqual:
+			v = v&^mask | q[i-1]&mask
+		case bitEqualSecond:
+			v = v&^mask | q[i-2]&mask
+		case bitNotEqual:
+			v = v&^mask | ^q[i-1]&mask
+		}
+	}
+	return v
+}
+
+// conditionsSatisfied reports whether q[from] to q[to] all satisfy their
+// conditions.
+func conditionsSatisfied(q *[wangStates]uint32, from, to int) bool {
+	for i := from; i <= to; i++ {
+		if satisfyConditions(q, i) != q[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func md4BlockWords(block []byte) [16]uint32 {
+	if len(block) != md4.BlockSize {
+		panic("block not a single MD4 block")
+	}
+	var m [16]uint32
+	for i := range m {
+		m[i] = binary.LittleEndian.Uint32(block[i*4:])
+	}
+	return m
+}
+
+func md4WordsBlock(m [16]uint32) []byte {
+	block := make([]byte, md4.BlockSize)
+	for i, w := range m {
+		binary.LittleEndian.PutUint32(block[i*4:], w)
+	}
+	return block
+}
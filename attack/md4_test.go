@@ -0,0 +1,32 @@
+package attack
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/md4"
+)
+
+func TestModifyMD4Message(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		m := md4BlockWords(ModifyMD4Message(testutil.MustRandomBytes(md4.BlockSize)))
+		q := md4States(&m)
+		if !conditionsSatisfied(&q, 4, 19) {
+			t.Fatalf("want round 1 conditions satisfied, got: %x", m)
+		}
+	}
+}
+
+func TestFindMD4Collision(t *testing.T) {
+	m1, m2, err := FindMD4Collision(t.Logf)
+	if err != nil {
+		t.Fatalf("finding collision: %v", err)
+	}
+	if bytes.Equal(m1, m2) {
+		t.Fatalf("want distinct messages, got: '%x'", m1)
+	}
+	if h1, h2 := md4.Sum(m1), md4.Sum(m2); h1 != h2 {
+		t.Fatalf("want: '%x', got: '%x'", h1, h2)
+	}
+}
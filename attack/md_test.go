@@ -0,0 +1,70 @@
+package attack
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/saclark/cryptopals/md"
+)
+
+func TestFindMulticollision(t *testing.T) {
+	h := md.NewAESHash([]byte{0x00, 0x00})
+	n := 4
+	mc := FindMulticollision(h, h.IV, n)
+	if len(mc.Blocks) != n {
+		t.Fatalf("want: %d block pairs, got: %d", n, len(mc.Blocks))
+	}
+
+	want := h.Sum(mc.Message(0))
+	seen := make(map[string]bool)
+	for i := uint64(0); i < 1<<n; i++ {
+		m := mc.Message(i)
+		if seen[string(m)] {
+			t.Fatalf("message %d not distinct: '%x'", i, m)
+		}
+		seen[string(m)] = true
+		if got := h.Iterate(h.IV, m); !bytes.Equal(mc.State, got) {
+			t.Fatalf("message %d: want state: '%x', got: '%x'", i, mc.State, got)
+		}
+		if got := h.Sum(m); !bytes.Equal(want, got) {
+			t.Fatalf("message %d: want: '%x', got: '%x'", i, want, got)
+		}
+	}
+}
+
+func TestFindExpandableMessage(t *testing.T) {
+	h := md.NewAESHash([]byte{0x00, 0x00})
+	k := 4
+	e := FindExpandableMessage(h, h.IV, k)
+
+	for blocks := k; blocks <= k+(1<<k)-1; blocks++ {
+		m := e.Message(blocks)
+		if len(m) != blocks*h.BlockSize {
+			t.Fatalf("want: %d blocks, got: %d bytes", blocks, len(m))
+		}
+		if got := h.Iterate(h.IV, m); !bytes.Equal(e.State, got) {
+			t.Fatalf("%d blocks: want state: '%x', got: '%x'", blocks, e.State, got)
+		}
+	}
+}
+
+func TestFindSecondPreimage(t *testing.T) {
+	h := md.NewAESHash([]byte{0x12, 0x34})
+	target := bytes.Repeat([]byte("YELLOW SUBMARINE"), 40)
+	target = append(target, "!"...)
+
+	got, err := FindSecondPreimage(h, target, 5)
+	if err != nil {
+		t.Fatalf("finding second preimage: %v", err)
+	}
+	if bytes.Equal(target, got) || len(target) != len(got) {
+		t.Fatalf("want a different message of length %d, got: '%x'", len(target), got)
+	}
+	if want, got := h.Sum(target), h.Sum(got); !bytes.Equal(want, got) {
+		t.Fatalf("want: '%x', got: '%x'", want, got)
+	}
+
+	if _, err := FindSecondPreimage(h, target[:5*h.BlockSize], 5); err == nil {
+		t.Fatal("want error for short target, got nil")
+	}
+}
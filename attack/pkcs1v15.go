@@ -0,0 +1,214 @@
+package attack
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// interval is the closed interval [a, b].
+type interval struct {
+	a *big.Int
+	b *big.Int
+}
+
+// ExploitPKCS1v15PaddingOracle decrypts an RSA ciphertext using an oracle that
+// reports whether the plaintext of a ciphertext is PKCS#1 v1.5 conforming, that
+// is, whether it begins with the bytes 0x00 0x02 (Bleichenbacher's 1998
+// attack). It returns the full encoded message, padding included.
+//
+// If m is conforming, then 2B <= m < 3B, where B = 2^(8(k-2)) and k is the
+// size of the modulus in bytes. Multiplying the ciphertext by s^e multiplies
+// the plaintext by s, and when m*s mod n is also conforming we learn that
+// 2B <= m*s - r*n < 3B for some r, which narrows down the possible values of
+// m. Searching for such values of s, and narrowing the set of intervals that
+// could contain m each time one is found, eventually leaves a single value.
+//
+// When not nil, logf is used to log the attack's progress.
+func ExploitPKCS1v15PaddingOracle(
+	pub *rsa.PublicKey,
+	ciphertext []byte,
+	isConforming func(ciphertext []byte) bool,
+	logf func(format string, a ...any),
+) (*big.Int, error) {
+	k := pub.Size()
+	if k < 11 {
+		return nil, errors.New("attack: modulus too small for PKCS#1 v1.5")
+	}
+
+	var (
+		n     = pub.N
+		e     = big.NewInt(int64(pub.E))
+		one   = big.NewInt(1)
+		b2    = new(big.Int).Lsh(big.NewInt(2), uint(8*(k-2)))
+		b3    = new(big.Int).Lsh(big.NewInt(3), uint(8*(k-2)))
+		b3m1  = new(big.Int).Sub(b3, one)
+		c     = rsa.BytesToInt(ciphertext)
+		query = func(s *big.Int) bool {
+			// Test whether c * s^e, the encryption of m*s, is conforming.
+			cs := new(big.Int).Exp(s, e, n)
+			cs.Mul(cs, c).Mod(cs, n)
+			return isConforming(rsa.IntToBytes(cs, k))
+		}
+	)
+
+	// Step 1: Blinding. If the ciphertext is not already conforming, find a
+	// random s0 for which c * s0^e is, and attack that instead.
+	s0 := big.NewInt(1)
+	if !isConforming(rsa.IntToBytes(c, k)) {
+		for {
+			var err error
+			if s0, err = rand.Int(rand.Reader, n); err != nil {
+				return nil, fmt.Errorf("generating blinding value: %w", err)
+			}
+			if s0.Sign() > 0 && query(s0) {
+				break
+			}
+		}
+		c0 := new(big.Int).Exp(s0, e, n)
+		c.Mul(c, c0).Mod(c, n)
+	}
+
+	m := []interval{{a: new(big.Int).Set(b2), b: new(big.Int).Set(b3m1)}}
+	s := new(big.Int)
+	for i := 1; ; i++ {
+		switch {
+		case i == 1:
+			// Step 2a: Search for the smallest s >= n/3B that gives a
+			// conforming plaintext.
+			s = ceilDiv(n, b3)
+			for !query(s) {
+				s.Add(s, one)
+			}
+		case len(m) > 1:
+			// Step 2b: Search with more than one interval left for the next
+			// s that gives a conforming plaintext.
+			s.Add(s, one)
+			for !query(s) {
+				s.Add(s, one)
+			}
+		default:
+			// Step 2c: Search with one interval, [a, b], left. Choosing small
+			// r >= 2(b*s - 2B)/n and s in [(2B + r*n)/b, (3B + r*n)/a)
+			// roughly halves the interval with each step.
+			s = searchSingleInterval(n, b2, b3, s, m[0], query)
+		}
+
+		// Step 3: Narrow the set of solutions.
+		m = narrowIntervals(n, b2, b3m1, s, m)
+		if len(m) == 0 {
+			return nil, errors.New("attack: no intervals left; oracle is inconsistent")
+		}
+
+		if logf != nil {
+			logf("step %d: s = %d, %d interval(s), width of first: %d bits\n",
+				i, s, len(m), new(big.Int).Sub(m[0].b, m[0].a).BitLen())
+		}
+
+		// Step 4: Computing the solution.
+		if len(m) == 1 && m[0].a.Cmp(m[0].b) == 0 {
+			s0Inv, err := rsa.InvMod(s0, n)
+			if err != nil {
+				return nil, fmt.Errorf("inverting blinding value: %w", err)
+			}
+			plaintext := new(big.Int).Mul(m[0].a, s0Inv)
+			return plaintext.Mod(plaintext, n), nil
+		}
+	}
+}
+
+// searchSingleInterval implements step 2c of Bleichenbacher's attack, returning
+// the next s for which query returns true.
+func searchSingleInterval(
+	n, b2, b3, prevS *big.Int,
+	m interval,
+	query func(s *big.Int) bool,
+) *big.Int {
+	// r = ceil(2(b*s - 2B)/n)
+	r := new(big.Int).Mul(m.b, prevS)
+	r.Sub(r, b2)
+	r.Lsh(r, 1)
+	r = ceilDiv(r, n)
+
+	rn := new(big.Int)
+	for ; ; r.Add(r, big.NewInt(1)) {
+		rn.Mul(r, n)
+		lo := ceilDiv(new(big.Int).Add(b2, rn), m.b)
+		hi := ceilDiv(new(big.Int).Add(b3, rn), m.a)
+		for s := lo; s.Cmp(hi) < 0; s.Add(s, big.NewInt(1)) {
+			if query(s) {
+				return s
+			}
+		}
+	}
+}
+
+// narrowIntervals implements step 3 of Bleichenbacher's attack. Given that m*s
+// mod n is conforming, each interval [a, b] that could contain m is narrowed
+// to the union of [max(a, ceil((2B + r*n)/s)), min(b, floor((3B-1 + r*n)/s))]
+// for each r in [ceil((a*s - 3B + 1)/n), floor((b*s - 2B)/n)]. Overlapping
+// intervals are merged.
+func narrowIntervals(n, b2, b3m1, s *big.Int, m []interval) []interval {
+	var narrowed []interval
+	for _, iv := range m {
+		rLo := new(big.Int).Mul(iv.a, s)
+		rLo.Sub(rLo, b3m1)
+		rLo = ceilDiv(rLo, n)
+		rHi := new(big.Int).Mul(iv.b, s)
+		rHi.Sub(rHi, b2)
+		rHi = floorDiv(rHi, n)
+
+		for r := rLo; r.Cmp(rHi) <= 0; r.Add(r, big.NewInt(1)) {
+			rn := new(big.Int).Mul(r, n)
+			a := ceilDiv(new(big.Int).Add(b2, rn), s)
+			if a.Cmp(iv.a) < 0 {
+				a.Set(iv.a)
+			}
+			b := floorDiv(new(big.Int).Add(b3m1, rn), s)
+			if b.Cmp(iv.b) > 0 {
+				b.Set(iv.b)
+			}
+			if a.Cmp(b) <= 0 {
+				narrowed = insertInterval(narrowed, interval{a: a, b: b})
+			}
+		}
+	}
+	return narrowed
+}
+
+// insertInterval adds iv to the sorted, disjoint intervals in m, merging it
+// with any intervals it overlaps.
+func insertInterval(m []interval, iv interval) []interval {
+	merged := make([]interval, 0, len(m)+1)
+	i := 0
+	for ; i < len(m) && m[i].b.Cmp(iv.a) < 0; i++ {
+		merged = append(merged, m[i])
+	}
+	for ; i < len(m) && m[i].a.Cmp(iv.b) <= 0; i++ {
+		if m[i].a.Cmp(iv.a) < 0 {
+			iv.a = m[i].a
+		}
+		if m[i].b.Cmp(iv.b) > 0 {
+			iv.b = m[i].b
+		}
+	}
+	merged = append(merged, iv)
+	return append(merged, m[i:]...)
+}
+
+// ceilDiv returns ceil(x/y) for y > 0.
+func ceilDiv(x, y *big.Int) *big.Int {
+	q, r := new(big.Int).DivMod(x, y, new(big.Int))
+	if r.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// floorDiv returns floor(x/y) for y > 0.
+func floorDiv(x, y *big.Int) *big.Int {
+	return new(big.Int).Div(x, y)
+}
@@ -0,0 +1,90 @@
+package attack
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestExploitPKCS1v15PaddingOracle(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 256, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	var calls int
+	isConforming := func(ciphertext []byte) bool {
+		calls++
+		m, err := rsa.Decrypt(priv, rsa.BytesToInt(ciphertext))
+		if err != nil {
+			t.Fatalf("decrypting: %v", err)
+		}
+		em := rsa.IntToBytes(m, priv.Size())
+		return em[0] == 0x00 && em[1] == 0x02
+	}
+
+	want := []byte("kick it, CC")
+	ciphertext, err := rsa.EncryptPKCS1v15(rand.Reader, &priv.PublicKey, want)
+	if err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+
+	m, err := ExploitPKCS1v15PaddingOracle(&priv.PublicKey, ciphertext, isConforming, nil)
+	if err != nil {
+		t.Fatalf("attacking: %v", err)
+	}
+	got := rsa.IntToBytes(m, priv.Size())
+	if !bytes.HasSuffix(got, append([]byte{0x00}, want...)) || got[0] != 0x00 || got[1] != 0x02 {
+		t.Fatalf("want encoded message ending in: '%x', got: '%x'", want, got)
+	}
+	t.Logf("oracle calls: %d", calls)
+}
+
+func TestInsertInterval(t *testing.T) {
+	iv := func(a, b int64) interval { return interval{a: big.NewInt(a), b: big.NewInt(b)} }
+	var m []interval
+	for _, x := range []interval{iv(10, 20), iv(40, 50), iv(0, 5), iv(18, 30), iv(60, 70), iv(29, 45)} {
+		m = insertInterval(m, x)
+	}
+
+	want := []interval{iv(0, 5), iv(10, 50), iv(60, 70)}
+	if len(m) != len(want) {
+		t.Fatalf("want %d intervals, got %d", len(want), len(m))
+	}
+	for i := range want {
+		if want[i].a.Cmp(m[i].a) != 0 || want[i].b.Cmp(m[i].b) != 0 {
+			t.Fatalf("interval %d: want: [%d, %d], got: [%d, %d]", i, want[i].a, want[i].b, m[i].a, m[i].b)
+		}
+	}
+}
+
+func TestExploitPKCS1v15PaddingOracle_NonConformingCiphertext(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 128, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	isConforming := func(ciphertext []byte) bool {
+		m, err := rsa.Decrypt(priv, rsa.BytesToInt(ciphertext))
+		if err != nil {
+			t.Fatalf("decrypting: %v", err)
+		}
+		em := rsa.IntToBytes(m, priv.Size())
+		return em[0] == 0x00 && em[1] == 0x02
+	}
+
+	want := new(big.Int).SetBytes([]byte("not padded"))
+	c, err := rsa.Encrypt(&priv.PublicKey, want)
+	if err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+
+	got, err := ExploitPKCS1v15PaddingOracle(&priv.PublicKey, rsa.IntToBytes(c, priv.Size()), isConforming, nil)
+	if err != nil {
+		t.Fatalf("attacking: %v", err)
+	}
+	if want.Cmp(got) != 0 {
+		t.Fatalf("want: %d, got: %d", want, got)
+	}
+}
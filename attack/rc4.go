@@ -0,0 +1,134 @@
+package attack
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+)
+
+// Positions of the RC4 keystream bytes with the strongest single-byte biases,
+// and the values they are biased towards. Z16 is 0xf0 with probability about
+// 2^-8(1 + 2^-4.8), and Z32 is 0xe0 with probability about 2^-8(1 + 2^-5.3).
+const (
+	rc4Z16     = 15
+	rc4Z16Bias = 0xf0
+	rc4Z32     = 31
+	rc4Z32Bias = 0xe0
+)
+
+// ExploitRC4SingleByteBiases recovers a secret of up to 32 bytes that is
+// appended to a chosen request and encrypted with RC4 under a fresh key each
+// time, using an oracle that returns the encryption of request || secret. The
+// oracle must be safe for concurrent use.
+//
+// The RC4 keystream is biased at a handful of positions, most strongly at the
+// 16th and 32nd bytes. Padding the request so that a byte of the secret lands
+// on one of those positions, and encrypting it many times, the most common
+// ciphertext byte there is most likely the biased keystream byte XORed with
+// the secret byte. Each request length puts one byte of the first half of the
+// secret at Z16 and one of the second half at Z32, so 16 request lengths
+// suffice, with samples encryptions each. Reliable recovery needs 2^24 or so.
+//
+// The encryptions are divided among workers goroutines, each keeping its own
+// frequency tables, which are merged when they are done.
+//
+// When not nil, logf is used to log the secret recovered so far.
+//
+// It panics if samples or workers is less than 1.
+func ExploitRC4SingleByteBiases(
+	oracle func(request []byte) []byte,
+	samples int,
+	workers int,
+	logf func(format string, a ...any),
+) ([]byte, error) {
+	if samples < 1 {
+		panic("samples not > 0")
+	}
+	if workers < 1 {
+		panic("workers not > 0")
+	}
+
+	secretLen := len(oracle(nil))
+	if secretLen > rc4Z32+1 {
+		return nil, fmt.Errorf("attack: secret of %d bytes longer than 32 bytes", secretLen)
+	}
+
+	secret := make([]byte, secretLen)
+	known := bytes.Repeat([]byte{'?'}, secretLen)
+	for padLen := 0; padLen <= rc4Z16; padLen++ {
+		z16, z32 := rc4Z16-padLen, rc4Z32-padLen
+		if z16 >= secretLen && z32 >= secretLen {
+			continue
+		}
+
+		counts16, counts32 := countRC4Biases(oracle, bytes.Repeat([]byte{'A'}, padLen), samples, workers)
+		if z16 < secretLen {
+			secret[z16] = mostFrequentByte(&counts16) ^ rc4Z16Bias
+			known[z16] = secret[z16]
+		}
+		if z32 < secretLen {
+			secret[z32] = mostFrequentByte(&counts32) ^ rc4Z32Bias
+			known[z32] = secret[z32]
+		}
+
+		if logf != nil {
+			logf("%q\n", known)
+		}
+	}
+
+	return secret, nil
+}
+
+// countRC4Biases encrypts request samples times, divided among workers
+// goroutines, and returns how often each ciphertext byte occurred at Z16 and
+// Z32.
+func countRC4Biases(
+	oracle func(request []byte) []byte,
+	request []byte,
+	samples int,
+	workers int,
+) (counts16, counts32 [256]uint64) {
+	shards16 := make([][256]uint64, workers)
+	shards32 := make([][256]uint64, workers)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		n := samples / workers
+		if w < samples%workers {
+			n++
+		}
+		wg.Add(1)
+		go func(w, n int) {
+			defer wg.Done()
+			for i := 0; i < n; i++ {
+				c := oracle(request)
+				if len(c) > rc4Z16 {
+					shards16[w][c[rc4Z16]]++
+				}
+				if len(c) > rc4Z32 {
+					shards32[w][c[rc4Z32]]++
+				}
+			}
+		}(w, n)
+	}
+	wg.Wait()
+
+	for w := 0; w < workers; w++ {
+		for b := 0; b < 256; b++ {
+			counts16[b] += shards16[w][b]
+			counts32[b] += shards32[w][b]
+		}
+	}
+	return counts16, counts32
+}
+
+// mostFrequentByte returns the byte with the highest count.
+func mostFrequentByte(counts *[256]uint64) byte {
+	var best byte
+	for b := 1; b < 256; b++ {
+		if counts[b] > counts[best] {
+			best = byte(b)
+		}
+	}
+	return best
+}
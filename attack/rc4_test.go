@@ -0,0 +1,58 @@
+package attack
+
+import (
+	"bytes"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/saclark/cryptopals/cipher"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+func TestExploitRC4SingleByteBiases(t *testing.T) {
+	secret := []byte("The biases are in the keystream!")
+
+	oracle := func(request []byte) []byte {
+		return testutil.FakeRC4Encrypt(append(append([]byte(nil), request...), secret...))
+	}
+
+	got, err := ExploitRC4SingleByteBiases(oracle, 1<<13, 4, nil)
+	if err != nil {
+		t.Fatalf("exploiting biases: %v", err)
+	}
+	if !bytes.Equal(secret, got) {
+		t.Fatalf("want: '%s', got: '%s'", secret, got)
+	}
+}
+
+func TestExploitRC4SingleByteBiases_SecretTooLong(t *testing.T) {
+	oracle := func(request []byte) []byte { return make([]byte, len(request)+33) }
+	if _, err := ExploitRC4SingleByteBiases(oracle, 1, 1, nil); err == nil {
+		t.Fatal("want error, got nil")
+	}
+}
+
+func TestRC4Z16Bias(t *testing.T) {
+	// The bias is only about 2^-4.8 of the uniform 2^-8, so a sample this
+	// small only shows it reliably with a fixed seed. Checking Z32, or a
+	// random sample, takes too long for a unit test.
+	const samples = 1 << 20
+	keys := mrand.New(mrand.NewSource(1))
+	key := make([]byte, 16)
+	keystream := make([]byte, rc4Z16+1)
+	count := 0
+	for i := 0; i < samples; i++ {
+		keys.Read(key)
+		for j := range keystream {
+			keystream[j] = 0
+		}
+		cipher.NewRC4(key).XORKeyStream(keystream, keystream)
+		if keystream[rc4Z16] == rc4Z16Bias {
+			count++
+		}
+	}
+
+	if uniform := samples / 256; count <= uniform {
+		t.Fatalf("want Z16 = %#x more than %d times, got: %d", rc4Z16Bias, uniform, count)
+	}
+}
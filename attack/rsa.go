@@ -0,0 +1,149 @@
+package attack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// ErrNoExactRoot is returned by RecoverRSABroadcastPlaintext when the value
+// combined from the ciphertexts is not a perfect e-th power.
+var ErrNoExactRoot = errors.New("attack: no exact root")
+
+// RSACiphertext is an RSA ciphertext, C, encrypted under the public key with
+// modulus N.
+type RSACiphertext struct {
+	N *big.Int
+	C *big.Int
+}
+
+// RecoverRSABroadcastPlaintext recovers a plaintext encrypted with textbook
+// RSA, without padding, under at least e different public keys which all use
+// the public exponent e (Håstad's broadcast attack).
+//
+// Since each c_i = m^e mod n_i, the Chinese Remainder Theorem gives us
+// m^e mod n_0*n_1*...*n_k. With at least e moduli, each larger than m, that
+// product is larger than m^e, so the result is m^e itself and we only need to
+// take its e-th root.
+//
+// It returns ErrInvalidModulus if any modulus is nil or less than 2,
+// ErrModuliNotCoprime if the moduli are not pairwise coprime and
+// ErrNoExactRoot if the combined value is not a perfect e-th power, which
+// means the ciphertexts were not all encryptions of the same plaintext.
+func RecoverRSABroadcastPlaintext(e int, ciphertexts []RSACiphertext) (*big.Int, error) {
+	if e < 1 {
+		return nil, fmt.Errorf("attack: invalid public exponent: %d", e)
+	}
+	if len(ciphertexts) < e {
+		return nil, fmt.Errorf("attack: need at least %d ciphertexts, got %d", e, len(ciphertexts))
+	}
+
+	residues := make([]*big.Int, len(ciphertexts))
+	moduli := make([]*big.Int, len(ciphertexts))
+	for i, c := range ciphertexts {
+		if c.N == nil || c.N.Cmp(big.NewInt(1)) <= 0 {
+			return nil, ErrInvalidModulus
+		}
+		if c.C == nil {
+			return nil, fmt.Errorf("attack: missing ciphertext %d", i)
+		}
+		residues[i] = c.C
+		moduli[i] = c.N
+	}
+
+	me, _, err := crt(residues, moduli)
+	if err != nil {
+		return nil, err
+	}
+
+	m, exact := root(me, e)
+	if !exact {
+		return nil, ErrNoExactRoot
+	}
+
+	return m, nil
+}
+
+// ForgePKCS1v15Signature forges an RSASSA-PKCS1-v1_5 SHA-1 signature of message
+// that is accepted by verifiers which do not check that the digest is
+// right-justified in the encoded message, such as rsa.VerifyPKCS1v15Sloppy
+// (Bleichenbacher's e=3 signature forgery).
+//
+// The forged encoded message is 0x00 || 0x01 || 0xff || 0x00 || DigestInfo,
+// followed by garbage. The signature is the smallest integer whose e-th power
+// is at least that encoded message with all-zero garbage. As long as the key is
+// large enough relative to e, its e-th power will not exceed the encoded
+// message with all-0xff garbage and so begins with the bytes we chose. No
+// modular reduction ever takes place, so the private key is not needed.
+func ForgePKCS1v15Signature(pub *rsa.PublicKey, message []byte) ([]byte, error) {
+	k := pub.Size()
+	prefix := append([]byte{0x00, 0x01, 0xff, 0x00}, rsa.SHA1DigestInfo(message)...)
+	if k <= len(prefix) {
+		return nil, rsa.ErrMessageTooLong
+	}
+
+	lo := make([]byte, k)
+	copy(lo, prefix)
+	hi := bytes.Repeat([]byte{0xff}, k)
+	copy(hi, prefix)
+
+	s, exact := root(rsa.BytesToInt(lo), pub.E)
+	if !exact {
+		s.Add(s, big.NewInt(1))
+	}
+
+	se := new(big.Int).Exp(s, big.NewInt(int64(pub.E)), nil)
+	if se.Cmp(rsa.BytesToInt(hi)) > 0 {
+		return nil, fmt.Errorf("attack: %d-bit key too small to forge signature with e = %d", pub.N.BitLen(), pub.E)
+	}
+
+	return rsa.IntToBytes(s, k), nil
+}
+
+// RecoverRSAPlaintextFromParityOracle decrypts ciphertext, one bit at a time,
+// using an oracle that reports whether the plaintext of a ciphertext is even.
+//
+// Multiplying the ciphertext by 2^e doubles the plaintext. Since N is odd,
+// 2m mod N is even if and only if 2m did not wrap the modulus, that is, if
+// m < N/2. Doubling again tells us which half of that half m is in, and so on,
+// halving the bounds on m with each oracle query until only one integer is
+// left. The bounds are tracked exactly as rationals.
+//
+// When not nil, logf is used to log the upper bound after each query, which
+// reveals the plaintext a little more each time.
+func RecoverRSAPlaintextFromParityOracle(
+	pub *rsa.PublicKey,
+	ciphertext *big.Int,
+	isEven func(ciphertext *big.Int) bool,
+	logf func(format string, a ...any),
+) *big.Int {
+	double := new(big.Int).Exp(big.NewInt(2), big.NewInt(int64(pub.E)), pub.N)
+	c := new(big.Int).Set(ciphertext)
+	lo, hi := new(big.Rat), new(big.Rat).SetInt(pub.N)
+	mid, two := new(big.Rat), big.NewRat(2, 1)
+
+	for i := 0; i < pub.N.BitLen(); i++ {
+		c.Mul(c, double).Mod(c, pub.N)
+		mid.Add(lo, hi).Quo(mid, two)
+		if isEven(c) {
+			hi.Set(mid)
+		} else {
+			lo.Set(mid)
+		}
+		if logf != nil {
+			hiInt := new(big.Int).Quo(hi.Num(), hi.Denom())
+			logf("%q\n", hiInt.Bytes())
+		}
+	}
+
+	// hi - lo = N/2^bits < 1 and lo <= m < hi, so m is the smallest integer no
+	// less than lo.
+	m := new(big.Int).Quo(lo.Num(), lo.Denom())
+	if new(big.Rat).SetInt(m).Cmp(lo) < 0 {
+		m.Add(m, big.NewInt(1))
+	}
+	return m
+}
@@ -0,0 +1,224 @@
+package attack
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestRoot(t *testing.T) {
+	tt := []struct {
+		x     int64
+		n     int
+		want  int64
+		exact bool
+	}{
+		{0, 3, 0, true},
+		{1, 3, 1, true},
+		{7, 3, 1, false},
+		{8, 3, 2, true},
+		{9, 3, 2, false},
+		{26, 3, 2, false},
+		{27, 3, 3, true},
+		{1 << 40, 2, 1 << 20, true},
+		{(1 << 40) - 1, 2, (1 << 20) - 1, false},
+		{1_000_000_007, 1, 1_000_000_007, true},
+		{3125, 5, 5, true},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("root(%d,%d)", tc.x, tc.n), func(t *testing.T) {
+			got, exact := root(big.NewInt(tc.x), tc.n)
+			if got.Cmp(big.NewInt(tc.want)) != 0 || exact != tc.exact {
+				t.Fatalf("want: %d (exact: %v), got: %d (exact: %v)", tc.want, tc.exact, got, exact)
+			}
+		})
+	}
+}
+
+func TestRoot_LargeValues(t *testing.T) {
+	for _, n := range []int{2, 3, 7} {
+		for i := 0; i < 20; i++ {
+			r, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 700))
+			if err != nil {
+				t.Fatalf("generating random int: %v", err)
+			}
+			x := new(big.Int).Exp(r, big.NewInt(int64(n)), nil)
+
+			got, exact := root(x, n)
+			if got.Cmp(r) != 0 || !exact {
+				t.Fatalf("n = %d: want: %d, got: %d (exact: %v)", n, r, got, exact)
+			}
+
+			got, exact = root(x.Add(x, big.NewInt(1)), n)
+			if got.Cmp(r) != 0 || exact {
+				t.Fatalf("n = %d: want: %d (inexact), got: %d (exact: %v)", n, r, got, exact)
+			}
+		}
+	}
+}
+
+func TestRecoverRSABroadcastPlaintext(t *testing.T) {
+	for _, e := range []int{3, 5} {
+		t.Run(fmt.Sprintf("e=%d", e), func(t *testing.T) {
+			want := new(big.Int).SetBytes([]byte("Man, it's a hot one"))
+
+			var ciphertexts []RSACiphertext
+			for i := 0; i < e; i++ {
+				priv, err := rsa.GenerateKey(rand.Reader, 512, e)
+				if err != nil {
+					t.Fatalf("generating key: %v", err)
+				}
+				c, err := rsa.Encrypt(&priv.PublicKey, want)
+				if err != nil {
+					t.Fatalf("encrypting: %v", err)
+				}
+				ciphertexts = append(ciphertexts, RSACiphertext{N: priv.N, C: c})
+			}
+
+			got, err := RecoverRSABroadcastPlaintext(e, ciphertexts)
+			if err != nil {
+				t.Fatalf("recovering plaintext: %v", err)
+			}
+			if want.Cmp(got) != 0 {
+				t.Fatalf("want: %d, got: %d", want, got)
+			}
+		})
+	}
+}
+
+func TestRecoverRSABroadcastPlaintext_ModuliNotCoprime(t *testing.T) {
+	p, q, r := big.NewInt(1009), big.NewInt(1013), big.NewInt(1019)
+	ciphertexts := []RSACiphertext{
+		{N: new(big.Int).Mul(p, q), C: big.NewInt(8)},
+		{N: new(big.Int).Mul(q, r), C: big.NewInt(8)},
+		{N: new(big.Int).Mul(p, r), C: big.NewInt(8)},
+	}
+
+	_, err := RecoverRSABroadcastPlaintext(3, ciphertexts)
+	if !errors.Is(err, ErrModuliNotCoprime) {
+		t.Fatalf("want: %v, got: %v", ErrModuliNotCoprime, err)
+	}
+}
+
+func TestRecoverRSABroadcastPlaintext_DifferentPlaintexts(t *testing.T) {
+	var ciphertexts []RSACiphertext
+	for i := 0; i < 3; i++ {
+		priv, err := rsa.GenerateKey(rand.Reader, 512, 3)
+		if err != nil {
+			t.Fatalf("generating key: %v", err)
+		}
+		c, err := rsa.Encrypt(&priv.PublicKey, big.NewInt(int64(1000+i)))
+		if err != nil {
+			t.Fatalf("encrypting: %v", err)
+		}
+		ciphertexts = append(ciphertexts, RSACiphertext{N: priv.N, C: c})
+	}
+
+	_, err := RecoverRSABroadcastPlaintext(3, ciphertexts)
+	if !errors.Is(err, ErrNoExactRoot) {
+		t.Fatalf("want: %v, got: %v", ErrNoExactRoot, err)
+	}
+}
+
+func TestRecoverRSABroadcastPlaintext_InvalidCiphertexts(t *testing.T) {
+	tests := []struct {
+		name    string
+		invalid RSACiphertext
+		want    error
+	}{
+		{"zero modulus", RSACiphertext{N: big.NewInt(0), C: big.NewInt(8)}, ErrInvalidModulus},
+		{"unit modulus", RSACiphertext{N: big.NewInt(1), C: big.NewInt(0)}, ErrInvalidModulus},
+		{"nil modulus", RSACiphertext{C: big.NewInt(8)}, ErrInvalidModulus},
+		{"nil ciphertext", RSACiphertext{N: big.NewInt(13)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ciphertexts := []RSACiphertext{
+				tt.invalid,
+				{N: big.NewInt(7), C: big.NewInt(1)},
+				{N: big.NewInt(11), C: big.NewInt(8)},
+			}
+			_, err := RecoverRSABroadcastPlaintext(3, ciphertexts)
+			if err == nil {
+				t.Fatal("want error, got nil")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Fatalf("want: %v, got: %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRecoverRSABroadcastPlaintext_TooFewCiphertexts(t *testing.T) {
+	_, err := RecoverRSABroadcastPlaintext(3, []RSACiphertext{{N: big.NewInt(15), C: big.NewInt(8)}})
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+}
+
+func TestForgePKCS1v15Signature(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	message := []byte("hi mom")
+
+	sig, err := ForgePKCS1v15Signature(&priv.PublicKey, message)
+	if err != nil {
+		t.Fatalf("forging signature: %v", err)
+	}
+
+	if err := rsa.VerifyPKCS1v15Sloppy(&priv.PublicKey, message, sig); err != nil {
+		t.Fatalf("sloppy verifier: want forged signature to verify, got: %v", err)
+	}
+	if err := rsa.VerifyPKCS1v15(&priv.PublicKey, message, sig); !errors.Is(err, rsa.ErrVerification) {
+		t.Fatalf("strict verifier: want: %v, got: %v", rsa.ErrVerification, err)
+	}
+}
+
+func TestForgePKCS1v15Signature_KeyTooSmall(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024, 65537)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	if _, err := ForgePKCS1v15Signature(&priv.PublicKey, []byte("hi mom")); err == nil {
+		t.Fatal("want error, got nil")
+	}
+}
+
+func TestRecoverRSAPlaintextFromParityOracle(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 512, 65537)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	isEven := func(c *big.Int) bool {
+		m, err := rsa.Decrypt(priv, c)
+		if err != nil {
+			t.Fatalf("decrypting: %v", err)
+		}
+		return m.Bit(0) == 0
+	}
+
+	for _, want := range []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).SetBytes([]byte("Even in the light of day")),
+		new(big.Int).Sub(priv.N, big.NewInt(1)),
+	} {
+		c, err := rsa.Encrypt(&priv.PublicKey, want)
+		if err != nil {
+			t.Fatalf("encrypting: %v", err)
+		}
+
+		got := RecoverRSAPlaintextFromParityOracle(&priv.PublicKey, c, isEven, nil)
+		if want.Cmp(got) != 0 {
+			t.Fatalf("want: %d, got: %d", want, got)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package cbcmac
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+
+	cpcipher "github.com/saclark/cryptopals/cipher"
+	"github.com/saclark/cryptopals/pkcs7"
+)
+
+// Sum returns the CBC-MAC of message: the last block of the CBC encryption of
+// the PKCS#7 padded message under block, starting from iv. The IV must have
+// length equal to the block size.
+//
+// CBC-MAC may be used with a fresh IV for each message, in which case the IV
+// must be authenticated along with the message, or with a fixed IV (typically
+// all zeros) shared by every message. Either way, it is only secure for
+// messages of a fixed length.
+func Sum(block cipher.Block, iv, message []byte) []byte {
+	blockSize := block.BlockSize()
+	padded := pkcs7.Pad(append([]byte(nil), message...), blockSize)
+	ciphertext := make([]byte, len(padded))
+	cpcipher.NewCBC(block, iv).Encrypt(ciphertext, padded)
+	return ciphertext[len(ciphertext)-blockSize:]
+}
+
+// Verify reports whether mac is the CBC-MAC of message under block and iv.
+func Verify(block cipher.Block, iv, message, mac []byte) bool {
+	return subtle.ConstantTimeCompare(Sum(block, iv, message), mac) == 1
+}
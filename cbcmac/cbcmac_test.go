@@ -0,0 +1,47 @@
+package cbcmac
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/pkcs7"
+)
+
+func TestSum_MatchesStdLibCBC(t *testing.T) {
+	block := testutil.Must(aes.NewCipher(testutil.MustRandomBytes(aes.BlockSize)))
+	iv := testutil.MustRandomBytes(aes.BlockSize)
+
+	for _, size := range []int{0, 1, 15, 16, 17, 100} {
+		message := testutil.MustRandomBytes(size)
+		padded := pkcs7.Pad(append([]byte(nil), message...), aes.BlockSize)
+		ciphertext := make([]byte, len(padded))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, padded)
+		want := ciphertext[len(ciphertext)-aes.BlockSize:]
+
+		got := Sum(block, iv, message)
+
+		if !bytes.Equal(want, got) {
+			t.Fatalf("size %d: want: '%x', got: '%x'", size, want, got)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	block := testutil.Must(aes.NewCipher(testutil.MustRandomBytes(aes.BlockSize)))
+	iv := make([]byte, aes.BlockSize)
+	message := []byte("from=1&tx_list=2:100")
+	mac := Sum(block, iv, message)
+
+	if !Verify(block, iv, message, mac) {
+		t.Fatal("want valid MAC to verify")
+	}
+	if Verify(block, iv, []byte("from=1&tx_list=2:900"), mac) {
+		t.Fatal("want MAC of different message not to verify")
+	}
+	if Verify(block, testutil.MustRandomBytes(aes.BlockSize), message, mac) {
+		t.Fatal("want MAC under different IV not to verify")
+	}
+}
@@ -0,0 +1,63 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"fmt"
+)
+
+// RC4 implements the RC4 stream cipher. It satisfies crypto/cipher.Stream. The
+// Go standard library already provides a proper implementation of this. This
+// was written as a learning exercise.
+type RC4 struct {
+	s    [256]byte
+	i, j uint8
+}
+
+var _ cipher.Stream = (*RC4)(nil)
+
+// NewRC4 returns a new RC4 keyed with key, which must be between 1 and 256
+// bytes long.
+func NewRC4(key []byte) *RC4 {
+	if len(key) < 1 || len(key) > 256 {
+		panic("cryptopals/cipher: invalid RC4 key size")
+	}
+
+	// Key-scheduling algorithm.
+	c := &RC4{}
+	for i := range c.s {
+		c.s[i] = byte(i)
+	}
+	var j uint8
+	for i := range c.s {
+		j += c.s[i] + key[i%len(key)]
+		c.s[i], c.s[j] = c.s[j], c.s[i]
+	}
+	return c
+}
+
+// XORKeyStream XORs each byte in src with a byte from the key stream, writing
+// the result to dst. Dst must have length >= src.
+func (c *RC4) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("cryptopals/cipher: output smaller than input")
+	}
+
+	// Pseudo-random generation algorithm.
+	i, j := c.i, c.j
+	for k, b := range src {
+		i++
+		j += c.s[i]
+		c.s[i], c.s[j] = c.s[j], c.s[i]
+		dst[k] = b ^ c.s[c.s[i]+c.s[j]]
+	}
+	c.i, c.j = i, j
+}
+
+func RC4Crypt(input, key []byte) ([]byte, error) {
+	if len(key) < 1 || len(key) > 256 {
+		return nil, fmt.Errorf("invalid RC4 key size: %d", len(key))
+	}
+	output := make([]byte, len(input))
+	NewRC4(key).XORKeyStream(output, input)
+	return output, nil
+}
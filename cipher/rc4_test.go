@@ -0,0 +1,54 @@
+package cipher
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rc4"
+	"fmt"
+	"testing"
+)
+
+func TestRC4_MatchesStdLibRC4(t *testing.T) {
+	plaintext := make([]byte, 300)
+	if _, err := rand.Read(plaintext); err != nil {
+		t.Fatalf("creating random test data: %v", err)
+	}
+
+	for _, keySize := range []int{1, 5, 16, 256} {
+		t.Run(fmt.Sprintf("key size %d", keySize), func(t *testing.T) {
+			key := make([]byte, keySize)
+			if _, err := rand.Read(key); err != nil {
+				t.Fatalf("creating random key: %v", err)
+			}
+			stdlib, err := rc4.NewCipher(key)
+			if err != nil {
+				t.Fatalf("creating stdlib cipher: %v", err)
+			}
+
+			// Encrypt in uneven pieces to check the state carries over.
+			want := make([]byte, len(plaintext))
+			stdlib.XORKeyStream(want, plaintext)
+			got := make([]byte, len(plaintext))
+			c := NewRC4(key)
+			c.XORKeyStream(got[:7], plaintext[:7])
+			c.XORKeyStream(got[7:], plaintext[7:])
+			if !bytes.Equal(want, got) {
+				t.Fatalf("want: '%x', got: '%x'", want, got)
+			}
+
+			decrypted, err := RC4Crypt(got, key)
+			if err != nil {
+				t.Fatalf("decrypting: %v", err)
+			}
+			if !bytes.Equal(plaintext, decrypted) {
+				t.Fatalf("want decrypted bytes: '%x', got decrypted bytes: '%x'", plaintext, decrypted)
+			}
+		})
+	}
+}
+
+func TestRC4Crypt_InvalidKeySize(t *testing.T) {
+	if _, err := RC4Crypt([]byte("YELLOW SUBMARINE"), nil); err == nil {
+		t.Fatal("want error for empty key, got nil")
+	}
+}
@@ -0,0 +1,90 @@
+package dh
+
+import (
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/saclark/cryptopals/sha1"
+)
+
+// The size of a key derived by DeriveKey, in bytes.
+const KeySize = 16
+
+const nistPHex = "ffffffffffffffffc90fdaa22168c234c4c6628b80dc1cd129024e088a67cc" +
+	"74020bbea63b139b22514a08798e3404ddef9519b3cd3a431b302b0a6df25f1437" +
+	"4fe1356d6d51c245e485b576625e7ec6f44c42e9a637ed6b0bff5cb6f406b7edee" +
+	"386bfb5a899fa5ae9f24117c4b1fe649286651ece45b3dc2007cb8a163bf0598da" +
+	"48361c55d39a69163fa8fd24cf5f83655d23dca3ad961c62f356208552bb9ed529" +
+	"077096966d670c354e4abc9804f1746c08ca237327ffffffffffffffff"
+
+// Group is a finite field Diffie-Hellman group, consisting of a prime modulus,
+// P, and a generator, G. If G generates a subgroup of known prime order Q,
+// then Q may also be given.
+type Group struct {
+	P *big.Int
+	G *big.Int
+	Q *big.Int
+}
+
+// NISTGroup returns the 1536-bit MODP group from RFC 3526 with a generator of
+// 2. A new Group is returned on each call, so callers are free to modify it.
+func NISTGroup() *Group {
+	p, _ := new(big.Int).SetString(nistPHex, 16)
+	return &Group{P: p, G: big.NewInt(2)}
+}
+
+// PrivateKey is a Diffie-Hellman private key, X, along with its corresponding
+// public key, Y = G^X mod P.
+type PrivateKey struct {
+	Group *Group
+	X     *big.Int
+	Y     *big.Int
+}
+
+// ErrInvalidGroup is returned when a group is missing parameters or its modulus
+// is too small to generate a key in.
+var ErrInvalidGroup = errors.New("dh: invalid group")
+
+// GenerateKey generates a private key in the group, reading random bytes from
+// rand, which will typically be crypto/rand.Reader. X is chosen uniformly
+// from [1, Q-1] if Q is given, or else from [1, P-2].
+func GenerateKey(group *Group, rand io.Reader) (*PrivateKey, error) {
+	if group.P == nil || group.G == nil || group.P.Cmp(big.NewInt(3)) < 0 {
+		return nil, ErrInvalidGroup
+	}
+	if group.Q != nil && group.Q.Cmp(big.NewInt(2)) < 0 {
+		return nil, ErrInvalidGroup
+	}
+
+	max := new(big.Int).Sub(group.P, big.NewInt(2))
+	if group.Q != nil {
+		max.Sub(group.Q, big.NewInt(1))
+	}
+	x, err := cryptorand.Int(rand, max)
+	if err != nil {
+		return nil, fmt.Errorf("generating private key: %w", err)
+	}
+	x.Add(x, big.NewInt(1))
+
+	return &PrivateKey{
+		Group: group,
+		X:     x,
+		Y:     new(big.Int).Exp(group.G, x, group.P),
+	}, nil
+}
+
+// SharedSecret returns peerPublicKey^X mod P. The peer's public key is not
+// validated in any way.
+func (k *PrivateKey) SharedSecret(peerPublicKey *big.Int) *big.Int {
+	return new(big.Int).Exp(peerPublicKey, k.X, k.Group.P)
+}
+
+// DeriveKey derives a KeySize byte AES key from a shared secret by taking the
+// first KeySize bytes of the SHA-1 checksum of the secret's big-endian bytes.
+func DeriveKey(secret *big.Int) []byte {
+	sum := sha1.Sum(secret.Bytes())
+	return sum[:KeySize]
+}
@@ -0,0 +1,131 @@
+package dh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/cipher"
+	"github.com/saclark/cryptopals/pkcs7"
+)
+
+func TestNISTGroup_ModulusIsPrime(t *testing.T) {
+	g := NISTGroup()
+	if got := g.P.BitLen(); got != 1536 {
+		t.Fatalf("want bit length: 1536, got: %d", got)
+	}
+	if !g.P.ProbablyPrime(20) {
+		t.Fatalf("want prime modulus, got: %x", g.P)
+	}
+}
+
+func TestSharedSecret_BothPartiesAgree(t *testing.T) {
+	tt := []struct {
+		name  string
+		group *Group
+	}{
+		{"p=37,g=5", &Group{P: big.NewInt(37), G: big.NewInt(5)}},
+		{"NIST", NISTGroup()},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				a, err := GenerateKey(tc.group, rand.Reader)
+				if err != nil {
+					t.Fatalf("generating key a: %v", err)
+				}
+				b, err := GenerateKey(tc.group, rand.Reader)
+				if err != nil {
+					t.Fatalf("generating key b: %v", err)
+				}
+
+				sa := a.SharedSecret(b.Y)
+				sb := b.SharedSecret(a.Y)
+				if sa.Cmp(sb) != 0 {
+					t.Fatalf("want: %x, got: %x", sa, sb)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateKey_UsesProvidedRandomness(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, 1024)
+
+	k1, err := GenerateKey(NISTGroup(), bytes.NewReader(seed))
+	if err != nil {
+		t.Fatalf("generating key 1: %v", err)
+	}
+	k2, err := GenerateKey(NISTGroup(), bytes.NewReader(seed))
+	if err != nil {
+		t.Fatalf("generating key 2: %v", err)
+	}
+
+	if k1.X.Cmp(k2.X) != 0 {
+		t.Fatalf("want: %x, got: %x", k1.X, k2.X)
+	}
+}
+
+func TestGenerateKey_SubgroupOrder(t *testing.T) {
+	// 2 generates the subgroup of order 11 in the integers mod 23.
+	group := &Group{P: big.NewInt(23), G: big.NewInt(2), Q: big.NewInt(11)}
+	for i := 0; i < 100; i++ {
+		k, err := GenerateKey(group, rand.Reader)
+		if err != nil {
+			t.Fatalf("generating key: %v", err)
+		}
+		if k.X.Sign() <= 0 || k.X.Cmp(group.Q) >= 0 {
+			t.Fatalf("want X in [1, %d], got: %d", group.Q.Int64()-1, k.X)
+		}
+	}
+}
+
+func TestGenerateKey_InvalidGroup(t *testing.T) {
+	tt := []*Group{
+		{},
+		{P: big.NewInt(2), G: big.NewInt(1)},
+		{P: big.NewInt(23), G: big.NewInt(2), Q: big.NewInt(1)},
+	}
+
+	for _, group := range tt {
+		_, err := GenerateKey(group, rand.Reader)
+		if !errors.Is(err, ErrInvalidGroup) {
+			t.Errorf("want: %v, got: %v", ErrInvalidGroup, err)
+		}
+	}
+}
+
+func TestDeriveKey_CBCEncryptThenDecrypt(t *testing.T) {
+	a, err := GenerateKey(NISTGroup(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key a: %v", err)
+	}
+	b, err := GenerateKey(NISTGroup(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key b: %v", err)
+	}
+
+	aKey := DeriveKey(a.SharedSecret(b.Y))
+	bKey := DeriveKey(b.SharedSecret(a.Y))
+	if len(aKey) != KeySize {
+		t.Fatalf("want key size: %d, got: %d", KeySize, len(aKey))
+	}
+
+	iv := make([]byte, 16)
+	plaintext := pkcs7.Pad([]byte("YELLOW SUBMARINE"), 16)
+	ciphertext, err := cipher.CBCEncrypt(plaintext, aKey, iv)
+	if err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	decrypted, err := cipher.CBCDecrypt(ciphertext, bKey, iv)
+	if err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+
+	if !bytes.Equal(plaintext, decrypted) {
+		t.Fatalf("want: '%x', got: '%x'", plaintext, decrypted)
+	}
+}
@@ -0,0 +1,243 @@
+package dsa
+
+import (
+	cryptorand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/saclark/cryptopals/rsa"
+	"github.com/saclark/cryptopals/sha1"
+)
+
+const (
+	cryptopalsPHex = "800000000000000089e1855218a0e7dac38136ffafa72eda7" +
+		"859f2171e25e65eac698c1702578b07dc2a1076da241c76c6" +
+		"2d374d8389ea5aeffd3226a0530cc565f3bf6b50929139ebe" +
+		"ac04f48c3c84afb796d61e5a4f9a8fda812ab59494232c7d2" +
+		"b4deb50aa18ee9e132bfa85ac4374d7f9091abc3d015efc87" +
+		"1a584471bb1"
+	cryptopalsQHex = "f4f47f05794b256174bba6e9b396a7707e563c5b"
+	cryptopalsGHex = "5958c9d3898b224b12672c0b98e06c60df923cb8bc999d11" +
+		"9458fef538b8fa4046c8db53039db620c094c9fa077ef389b5" +
+		"322a559946a71903f990f1f7e0e025e2d7f7cf494aff1a0470" +
+		"f5b64c36b625a097f1651fe775323556fe00b3608c88789287" +
+		"8480e99041be601a62166ca6894bdd41a7054ec89f756ba9fc" +
+		"95302291"
+)
+
+var (
+	// ErrInvalidParameters is returned when domain parameters are missing or
+	// unusable.
+	ErrInvalidParameters = errors.New("dsa: invalid parameters")
+	// ErrInvalidNonce is returned by SignWithNonce when the nonce is not in
+	// [1, Q) or produces a zero r or s.
+	ErrInvalidNonce = errors.New("dsa: invalid nonce")
+	// ErrVerification is returned when a signature fails to verify.
+	ErrVerification = errors.New("dsa: verification error")
+)
+
+// Parameters are the DSA domain parameters: a prime modulus, P, a prime
+// divisor of P-1, Q, and a generator of the order Q subgroup, G.
+type Parameters struct {
+	P *big.Int
+	Q *big.Int
+	G *big.Int
+}
+
+// CryptopalsParameters returns the 1024-bit domain parameters given by
+// cryptopals challenge 43. A new Parameters is returned on each call, so
+// callers are free to modify it.
+func CryptopalsParameters() *Parameters {
+	p, _ := new(big.Int).SetString(cryptopalsPHex, 16)
+	q, _ := new(big.Int).SetString(cryptopalsQHex, 16)
+	g, _ := new(big.Int).SetString(cryptopalsGHex, 16)
+	return &Parameters{P: p, Q: q, G: g}
+}
+
+// Hash returns the SHA-1 checksum of message as an integer, truncated to the
+// bit length of Q if Q is shorter than the checksum.
+func (params *Parameters) Hash(message []byte) *big.Int {
+	sum := sha1.Sum(message)
+	h := new(big.Int).SetBytes(sum[:])
+	if excess := sha1.Size*8 - params.Q.BitLen(); excess > 0 {
+		h.Rsh(h, uint(excess))
+	}
+	return h
+}
+
+// PublicKey is a DSA public key, Y = G^X mod P.
+type PublicKey struct {
+	Parameters
+	Y *big.Int
+}
+
+// PrivateKey is a DSA private key, X, along with its public key.
+type PrivateKey struct {
+	PublicKey
+	X *big.Int
+}
+
+// NewPrivateKey returns the private key x with the given domain parameters.
+func NewPrivateKey(params *Parameters, x *big.Int) *PrivateKey {
+	return &PrivateKey{
+		PublicKey: PublicKey{
+			Parameters: *params,
+			Y:          new(big.Int).Exp(params.G, x, params.P),
+		},
+		X: x,
+	}
+}
+
+// Fingerprint returns the SHA-1 checksum of the lowercase hex encoding of x,
+// without leading zeros, which is how cryptopals identifies private keys.
+func Fingerprint(x *big.Int) sha1.Digest {
+	return sha1.Sum([]byte(x.Text(16)))
+}
+
+// GenerateKey generates a private key with the given domain parameters,
+// reading random bytes from rand, which will typically be crypto/rand.Reader.
+// X is chosen uniformly from [1, Q).
+//
+// A proper implementation exists in crypto/dsa. This was written as a learning
+// exercise.
+func GenerateKey(params *Parameters, rand io.Reader) (*PrivateKey, error) {
+	if !params.valid() {
+		return nil, ErrInvalidParameters
+	}
+	x, err := randomScalar(params.Q, rand)
+	if err != nil {
+		return nil, fmt.Errorf("generating private key: %w", err)
+	}
+	return NewPrivateKey(params, x), nil
+}
+
+// Sign signs message with a random nonce read from rand, returning the
+// signature (r, s). Nonces that produce a zero r or s are discarded and a new
+// one chosen.
+func Sign(priv *PrivateKey, rand io.Reader, message []byte) (r, s *big.Int, err error) {
+	return sign(priv, rand, message, true)
+}
+
+// SignLenient is like Sign but accepts signatures with a zero r or s, as
+// produced by maliciously chosen domain parameters such as G = 0. Do not use
+// it.
+func SignLenient(priv *PrivateKey, rand io.Reader, message []byte) (r, s *big.Int, err error) {
+	return sign(priv, rand, message, false)
+}
+
+// SignWithNonce signs message using the nonce k, returning the signature
+// (r, s). It returns ErrInvalidNonce if k is not in [1, Q) or if it produces a
+// zero r or s.
+//
+// The nonce must be secret, unpredictable and never reused. Anyone who knows or
+// can guess it can recover the private key.
+func SignWithNonce(priv *PrivateKey, k *big.Int, message []byte) (r, s *big.Int, err error) {
+	return signWithNonce(priv, k, message, true)
+}
+
+// Verify verifies the signature (r, s) of message. It returns ErrVerification
+// if the signature is not valid.
+func Verify(pub *PublicKey, message []byte, r, s *big.Int) error {
+	return verify(pub, message, r, s, true)
+}
+
+// VerifyLenient is like Verify but does not check that r and s are in (0, Q),
+// allowing signatures forged under maliciously chosen domain parameters such
+// as G = 0 to verify. Do not use it.
+func VerifyLenient(pub *PublicKey, message []byte, r, s *big.Int) error {
+	return verify(pub, message, r, s, false)
+}
+
+func sign(priv *PrivateKey, rand io.Reader, message []byte, strict bool) (r, s *big.Int, err error) {
+	if !priv.valid() {
+		return nil, nil, ErrInvalidParameters
+	}
+	for {
+		k, err := randomScalar(priv.Q, rand)
+		if err != nil {
+			return nil, nil, fmt.Errorf("generating nonce: %w", err)
+		}
+		r, s, err := signWithNonce(priv, k, message, strict)
+		if errors.Is(err, ErrInvalidNonce) {
+			continue
+		}
+		return r, s, err
+	}
+}
+
+func signWithNonce(priv *PrivateKey, k *big.Int, message []byte, strict bool) (r, s *big.Int, err error) {
+	if !priv.valid() {
+		return nil, nil, ErrInvalidParameters
+	}
+	if k.Sign() <= 0 || k.Cmp(priv.Q) >= 0 {
+		return nil, nil, ErrInvalidNonce
+	}
+
+	r = new(big.Int).Exp(priv.G, k, priv.P)
+	r.Mod(r, priv.Q)
+	if strict && r.Sign() == 0 {
+		return nil, nil, ErrInvalidNonce
+	}
+
+	kInv, err := rsa.InvMod(k, priv.Q)
+	if err != nil {
+		return nil, nil, ErrInvalidNonce
+	}
+
+	// s = k^-1 (H(m) + x*r) mod q
+	s = new(big.Int).Mul(priv.X, r)
+	s.Add(s, priv.Hash(message))
+	s.Mul(s, kInv)
+	s.Mod(s, priv.Q)
+	if strict && s.Sign() == 0 {
+		return nil, nil, ErrInvalidNonce
+	}
+
+	return r, s, nil
+}
+
+func verify(pub *PublicKey, message []byte, r, s *big.Int, strict bool) error {
+	if !pub.valid() {
+		return ErrInvalidParameters
+	}
+	if strict && (r.Sign() <= 0 || r.Cmp(pub.Q) >= 0 || s.Sign() <= 0 || s.Cmp(pub.Q) >= 0) {
+		return ErrVerification
+	}
+
+	w, err := rsa.InvMod(s, pub.Q)
+	if err != nil {
+		return ErrVerification
+	}
+
+	// v = (g^u1 * y^u2 mod p) mod q, where u1 = H(m)*w and u2 = r*w.
+	u1 := new(big.Int).Mul(pub.Hash(message), w)
+	u1.Mod(u1, pub.Q)
+	u2 := new(big.Int).Mul(r, w)
+	u2.Mod(u2, pub.Q)
+	v := new(big.Int).Exp(pub.G, u1, pub.P)
+	v.Mul(v, new(big.Int).Exp(pub.Y, u2, pub.P))
+	v.Mod(v, pub.P)
+	v.Mod(v, pub.Q)
+
+	if v.Cmp(r) != 0 {
+		return ErrVerification
+	}
+	return nil
+}
+
+func (params *Parameters) valid() bool {
+	return params.P != nil && params.Q != nil && params.G != nil &&
+		params.P.Sign() > 0 && params.Q.Cmp(big.NewInt(1)) > 0
+}
+
+// randomScalar returns a uniformly random integer in [1, q).
+func randomScalar(q *big.Int, rand io.Reader) (*big.Int, error) {
+	max := new(big.Int).Sub(q, big.NewInt(1))
+	k, err := cryptorand.Int(rand, max)
+	if err != nil {
+		return nil, err
+	}
+	return k.Add(k, big.NewInt(1)), nil
+}
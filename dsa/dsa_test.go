@@ -0,0 +1,150 @@
+package dsa
+
+import (
+	stddsa "crypto/dsa"
+	"crypto/rand"
+	stdsha1 "crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestCryptopalsParameters(t *testing.T) {
+	params := CryptopalsParameters()
+	if !params.P.ProbablyPrime(20) {
+		t.Fatal("want P prime")
+	}
+	if !params.Q.ProbablyPrime(20) {
+		t.Fatal("want Q prime")
+	}
+	pMinusOne := new(big.Int).Sub(params.P, big.NewInt(1))
+	if new(big.Int).Mod(pMinusOne, params.Q).Sign() != 0 {
+		t.Fatal("want Q to divide P-1")
+	}
+	if new(big.Int).Exp(params.G, params.Q, params.P).Cmp(big.NewInt(1)) != 0 {
+		t.Fatal("want G to have order Q")
+	}
+}
+
+func TestSign_VerifiesWithStdLib(t *testing.T) {
+	params := CryptopalsParameters()
+	priv, err := GenerateKey(params, rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	message := []byte("hello, world")
+	r, s, err := Sign(priv, rand.Reader, message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	stdPub := &stddsa.PublicKey{
+		Parameters: stddsa.Parameters{P: params.P, Q: params.Q, G: params.G},
+		Y:          priv.Y,
+	}
+	digest := stdsha1.Sum(message)
+	if !stddsa.Verify(stdPub, digest[:], r, s) {
+		t.Fatal("want crypto/dsa to verify signature")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	priv, err := GenerateKey(CryptopalsParameters(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	message := []byte("hello, world")
+	r, s, err := Sign(priv, rand.Reader, message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	if err := Verify(&priv.PublicKey, message, r, s); err != nil {
+		t.Fatalf("verifying valid signature: %v", err)
+	}
+	if err := Verify(&priv.PublicKey, []byte("goodbye, world"), r, s); !errors.Is(err, ErrVerification) {
+		t.Fatalf("wrong message: want: %v, got: %v", ErrVerification, err)
+	}
+	if err := Verify(&priv.PublicKey, message, r, new(big.Int).Add(s, big.NewInt(1))); !errors.Is(err, ErrVerification) {
+		t.Fatalf("tampered s: want: %v, got: %v", ErrVerification, err)
+	}
+	if err := Verify(&priv.PublicKey, message, big.NewInt(0), s); !errors.Is(err, ErrVerification) {
+		t.Fatalf("zero r: want: %v, got: %v", ErrVerification, err)
+	}
+	if err := Verify(&priv.PublicKey, message, r, new(big.Int).Add(s, priv.Q)); !errors.Is(err, ErrVerification) {
+		t.Fatalf("s out of range: want: %v, got: %v", ErrVerification, err)
+	}
+}
+
+func TestSignWithNonce(t *testing.T) {
+	priv, err := GenerateKey(CryptopalsParameters(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	message := []byte("hello, world")
+
+	r1, s1, err := SignWithNonce(priv, big.NewInt(12345), message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	r2, s2, err := SignWithNonce(priv, big.NewInt(12345), message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	if r1.Cmp(r2) != 0 || s1.Cmp(s2) != 0 {
+		t.Fatal("want identical signatures for identical nonces")
+	}
+	if err := Verify(&priv.PublicKey, message, r1, s1); err != nil {
+		t.Fatalf("verifying: %v", err)
+	}
+
+	for _, k := range []*big.Int{big.NewInt(0), priv.Q} {
+		if _, _, err := SignWithNonce(priv, k, message); !errors.Is(err, ErrInvalidNonce) {
+			t.Fatalf("k = %d: want: %v, got: %v", k, ErrInvalidNonce, err)
+		}
+	}
+}
+
+func TestSignLenient_ZeroR(t *testing.T) {
+	params := CryptopalsParameters()
+	params.G = big.NewInt(0)
+	priv, err := GenerateKey(params, rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	message := []byte("hello, world")
+
+	if _, _, err := SignWithNonce(priv, big.NewInt(12345), message); !errors.Is(err, ErrInvalidNonce) {
+		t.Fatalf("strict: want: %v, got: %v", ErrInvalidNonce, err)
+	}
+
+	r, s, err := SignLenient(priv, rand.Reader, message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	if err := VerifyLenient(&priv.PublicKey, message, r, s); err != nil {
+		t.Fatalf("lenient: want nil, got: %v", err)
+	}
+	if err := Verify(&priv.PublicKey, message, r, s); !errors.Is(err, ErrVerification) {
+		t.Fatalf("strict: want: %v, got: %v", ErrVerification, err)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		x    int64
+		want string
+	}{
+		{0xabc, "a9993e364706816aba3e25717850c26c9cd0d89d"},  // sha1("abc")
+		{0xabcd, "81fe8bfe87576c3ecb22426f8e57847382917acf"}, // sha1("abcd")
+	}
+	for _, tt := range tests {
+		got := Fingerprint(big.NewInt(tt.x))
+		if hex.EncodeToString(got[:]) != tt.want {
+			t.Fatalf("x = %x: want: '%s', got: '%x'", tt.x, tt.want, got)
+		}
+	}
+}
@@ -8,22 +8,6 @@ import (
 	"testing"
 )
 
-type sha256Hash struct{}
-
-func (sha256Hash) Size() int {
-	return sha256.Size
-}
-
-func (sha256Hash) BlockSize() int {
-	return sha256.BlockSize
-}
-
-func (sha256Hash) Sum(message []byte) []byte {
-	h := sha256.New()
-	h.Write(message)
-	return h.Sum(nil)
-}
-
 func TestSum(t *testing.T) {
 	bs := sha256.New().BlockSize()
 	for i := 0; i < bs*2; i++ {
@@ -35,7 +19,7 @@ func TestSum(t *testing.T) {
 			stdHMAC.Write(message)
 			want := stdHMAC.Sum(nil)
 
-			thisHMAC := New(sha256Hash{}, key)
+			thisHMAC := New(SHA256{}, key)
 			got := thisHMAC.Sum(message)
 
 			if !bytes.Equal(want, got) {
@@ -0,0 +1,19 @@
+package hmac
+
+import "crypto/sha256"
+
+// SHA256 wraps package crypto/sha256 in the Hash interface.
+type SHA256 struct{}
+
+func (SHA256) Size() int {
+	return sha256.Size
+}
+
+func (SHA256) BlockSize() int {
+	return sha256.BlockSize
+}
+
+func (SHA256) Sum(message []byte) []byte {
+	sum := sha256.Sum256(message)
+	return sum[:]
+}
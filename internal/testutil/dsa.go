@@ -0,0 +1,84 @@
+package testutil
+
+import (
+	"bufio"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// DSASignatureRecord is a signed message as found in the cryptopals challenge
+// 44 data file: the message, the signature (R, S) and the message's SHA-1
+// digest as an integer, M.
+type DSASignatureRecord struct {
+	Message []byte
+	R       *big.Int
+	S       *big.Int
+	M       *big.Int
+}
+
+func MustParseDSASignatureFile(filepath string) []DSASignatureRecord {
+	return Must(parseDSASignatureFile(filepath))
+}
+
+// parseDSASignatureFile parses records of four consecutive lines of the form:
+//
+//	msg: <message>
+//	s: <decimal integer>
+//	r: <decimal integer>
+//	m: <hex encoded SHA-1 digest>
+//
+// Message values are taken verbatim, including any trailing whitespace. Digests
+// are parsed as integers, since the challenge file omits their leading zeros.
+func parseDSASignatureFile(filepath string) ([]DSASignatureRecord, error) {
+	file, err := os.Open(filepath)
+	if err != nil {
+		return nil, fmt.Errorf("opening file: %v", err)
+	}
+	defer file.Close()
+
+	var records []DSASignatureRecord
+	var record DSASignatureRecord
+	keys := []string{"msg", "s", "r", "m"}
+	scanner := bufio.NewScanner(file)
+	for i := 0; scanner.Scan(); i++ {
+		line := scanner.Text()
+		key := keys[i%len(keys)]
+		value, ok := strings.CutPrefix(line, key+": ")
+		if !ok {
+			return nil, fmt.Errorf("line %d: want prefix '%s: ', got line '%s'", i+1, key, line)
+		}
+
+		switch key {
+		case "msg":
+			record = DSASignatureRecord{Message: []byte(value)}
+		case "s", "r":
+			n, ok := new(big.Int).SetString(value, 10)
+			if !ok {
+				return nil, fmt.Errorf("line %d: parsing '%s' as a decimal integer", i+1, value)
+			}
+			if key == "s" {
+				record.S = n
+			} else {
+				record.R = n
+			}
+		case "m":
+			n, ok := new(big.Int).SetString(value, 16)
+			if !ok {
+				return nil, fmt.Errorf("line %d: parsing '%s' as a hex integer", i+1, value)
+			}
+			record.M = n
+			records = append(records, record)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning file: %v", err)
+	}
+	if record.M == nil {
+		return nil, fmt.Errorf("incomplete final record")
+	}
+
+	return records, nil
+}
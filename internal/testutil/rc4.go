@@ -0,0 +1,20 @@
+package testutil
+
+// FakeRC4Encrypt encrypts plaintext with a random keystream in which the 16th
+// byte is 0xf0, and the 32nd byte 0xe0, one time in 16 rather than the roughly
+// one in 247 of RC4, so that attacks on those biases can be tested quickly.
+func FakeRC4Encrypt(plaintext []byte) []byte {
+	keystream := MustRandomBytes(len(plaintext))
+	r := MustRandomBytes(2)
+	if len(keystream) > 15 && r[0] < 16 {
+		keystream[15] = 0xf0
+	}
+	if len(keystream) > 31 && r[1] < 16 {
+		keystream[31] = 0xe0
+	}
+	ciphertext := make([]byte, len(plaintext))
+	for i := range plaintext {
+		ciphertext[i] = plaintext[i] ^ keystream[i]
+	}
+	return ciphertext
+}
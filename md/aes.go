@@ -0,0 +1,46 @@
+package md
+
+import (
+	"crypto/aes"
+	"encoding/binary"
+	"fmt"
+)
+
+// AESCompress is a compression function built on AES. The next state is the
+// AES encryption of block, under the state zero-padded to a 128-bit key,
+// truncated to the length of the state. It panics if the state is longer than
+// 16 bytes or the block is not exactly 16 bytes.
+func AESCompress(state, block []byte) []byte {
+	if len(state) > aes.BlockSize {
+		panic("md: AES compression state longer than 16 bytes")
+	}
+	if len(block) != aes.BlockSize {
+		panic("md: AES compression block not 16 bytes")
+	}
+
+	key := make([]byte, aes.BlockSize)
+	copy(key, state)
+	c, err := aes.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+	out := make([]byte, aes.BlockSize)
+	c.Encrypt(out, block)
+	return out[:len(state)]
+}
+
+// NewAESHash returns a hash, built on AESCompress, whose state and digest are
+// the length of iv. With a short iv this is a deliberately weak hash, for which
+// collisions can be found by brute force. It panics if iv is empty or longer
+// than 16 bytes.
+func NewAESHash(iv []byte) *Hash {
+	if len(iv) == 0 || len(iv) > aes.BlockSize {
+		panic(fmt.Sprintf("md: invalid AES hash state size: %d", len(iv)))
+	}
+	return &Hash{
+		BlockSize: aes.BlockSize,
+		IV:        append([]byte(nil), iv...),
+		Compress:  AESCompress,
+		Pad:       LengthPadding(aes.BlockSize, binary.BigEndian),
+	}
+}
@@ -0,0 +1,85 @@
+package md
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// CompressFunc is the compression function of a Merkle-Damgård hash. It
+// returns the next chaining state after compressing block into state. It must
+// not modify state or block.
+type CompressFunc func(state, block []byte) []byte
+
+// PadFunc returns the padding to append to a message of msgLen bytes so that
+// its length is a multiple of the block size.
+type PadFunc func(msgLen uint64) []byte
+
+// Hash is a hash function built with the Merkle-Damgård construction: the
+// message is padded to a whole number of blocks, which are then compressed into
+// the chaining state one at a time, starting from IV. The final state is the
+// digest.
+type Hash struct {
+	// BlockSize is the size of a message block in bytes.
+	BlockSize int
+	// IV is the initial chaining state. Its length is the size of the state,
+	// and of the digest, in bytes.
+	IV []byte
+	// Compress is the compression function.
+	Compress CompressFunc
+	// Pad is the padding rule.
+	Pad PadFunc
+}
+
+// Size returns the size of the hash's state, and digest, in bytes.
+func (h *Hash) Size() int {
+	return len(h.IV)
+}
+
+// Sum returns the digest of message.
+func (h *Hash) Sum(message []byte) []byte {
+	return h.SumFromState(h.IV, 0, message)
+}
+
+// SumFromState returns the digest of data, starting from the chaining state
+// state as though initLen bytes of message had already been compressed into it.
+// The message length given to the padding rule is initLen + len(data).
+func (h *Hash) SumFromState(state []byte, initLen uint64, data []byte) []byte {
+	msgLen := initLen + uint64(len(data))
+	padded := append(append([]byte(nil), data...), h.Pad(msgLen)...)
+	return h.Iterate(state, padded)
+}
+
+// Iterate compresses each block of blocks into state, without padding, and
+// returns the resulting state. It panics if len(blocks) is not a multiple of
+// the block size.
+func (h *Hash) Iterate(state, blocks []byte) []byte {
+	if len(blocks)%h.BlockSize != 0 {
+		panic(fmt.Sprintf("md: input length %d not a multiple of block size %d", len(blocks), h.BlockSize))
+	}
+	state = append([]byte(nil), state...)
+	for ; len(blocks) > 0; blocks = blocks[h.BlockSize:] {
+		state = h.Compress(state, blocks[:h.BlockSize])
+	}
+	return state
+}
+
+// LengthPadding returns the padding rule used by MD4, MD5, SHA-1 and SHA-2 for
+// the given block size: a 0x80 byte, followed by as few zero bytes as needed
+// to leave exactly 8 bytes at the end of the last block, followed by the
+// message length in bits as a 64-bit integer in the given byte order. It panics
+// if blockSize is less than 9.
+func LengthPadding(blockSize int, order binary.ByteOrder) PadFunc {
+	if blockSize < 9 {
+		panic("md: block size too small for length padding")
+	}
+	return func(msgLen uint64) []byte {
+		padLen := blockSize - int(msgLen%uint64(blockSize))
+		if padLen < 9 {
+			padLen += blockSize
+		}
+		pad := make([]byte, padLen)
+		pad[0] = 0x80
+		order.PutUint64(pad[padLen-8:], msgLen*8)
+		return pad
+	}
+}
@@ -0,0 +1,49 @@
+package md
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestLengthPadding(t *testing.T) {
+	pad := LengthPadding(64, binary.BigEndian)
+	for msgLen := uint64(0); msgLen <= 130; msgLen++ {
+		p := pad(msgLen)
+		if (msgLen+uint64(len(p)))%64 != 0 {
+			t.Fatalf("[%d]: padded length %d not a multiple of block size", msgLen, msgLen+uint64(len(p)))
+		}
+		if len(p) < 9 || len(p) > 64+8 {
+			t.Fatalf("[%d]: invalid padding length: %d", msgLen, len(p))
+		}
+		if p[0] != 0x80 {
+			t.Fatalf("[%d]: want first byte: 0x80, got: %#x", msgLen, p[0])
+		}
+		if got := binary.BigEndian.Uint64(p[len(p)-8:]); got != msgLen*8 {
+			t.Fatalf("[%d]: want bit length: %d, got: %d", msgLen, msgLen*8, got)
+		}
+	}
+}
+
+func TestHash_SumFromState(t *testing.T) {
+	h := NewAESHash([]byte{0xAB, 0xCD, 0xEF})
+	message := []byte("Two blocks of message, and then some more")
+
+	prefix := message[:2*h.BlockSize]
+	state := h.Iterate(h.IV, prefix)
+	want := h.Sum(message)
+	got := h.SumFromState(state, uint64(len(prefix)), message[len(prefix):])
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%x', got: '%x'", want, got)
+	}
+}
+
+func TestNewAESHash(t *testing.T) {
+	h := NewAESHash([]byte{0x01, 0x02})
+	if h.Size() != 2 {
+		t.Fatalf("want size: 2, got: %d", h.Size())
+	}
+	if sum := h.Sum([]byte("YELLOW SUBMARINE")); len(sum) != 2 {
+		t.Fatalf("want digest length: 2, got: %d", len(sum))
+	}
+}
@@ -0,0 +1,125 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Conn is one end of an in-process, bidirectional message stream between two
+// actors. Messages may be of any type.
+type Conn struct {
+	send      chan<- any
+	recv      <-chan any
+	closeOnce *sync.Once
+}
+
+// Pipe returns two connected Conns. Messages sent on one are received on the
+// other. Sends block until the message is received.
+func Pipe() (*Conn, *Conn) {
+	ab := make(chan any)
+	ba := make(chan any)
+	a := &Conn{send: ab, recv: ba, closeOnce: &sync.Once{}}
+	b := &Conn{send: ba, recv: ab, closeOnce: &sync.Once{}}
+	return a, b
+}
+
+// Send sends msg to the other end of the connection. It returns ctx.Err() if
+// ctx is done before the message is received.
+func (c *Conn) Send(ctx context.Context, msg any) error {
+	select {
+	case c.send <- msg:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Recv receives a message from the other end of the connection. It returns
+// io.EOF if the other end has been closed, or ctx.Err() if ctx is done before
+// a message arrives.
+func (c *Conn) Recv(ctx context.Context) (any, error) {
+	select {
+	case msg, ok := <-c.recv:
+		if !ok {
+			return nil, io.EOF
+		}
+		return msg, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close signals to the other end of the connection that no more messages will
+// be sent. It is safe to call Close more than once.
+func (c *Conn) Close() {
+	c.closeOnce.Do(func() { close(c.send) })
+}
+
+// Receive receives a message from conn and returns an error if it is not of
+// type T.
+func Receive[T any](ctx context.Context, conn *Conn) (T, error) {
+	var zero T
+	msg, err := conn.Recv(ctx)
+	if err != nil {
+		return zero, err
+	}
+	v, ok := msg.(T)
+	if !ok {
+		return zero, fmt.Errorf("unexpected message type: want %T, got %T", zero, msg)
+	}
+	return v, nil
+}
+
+// Actor is a participant in a protocol. It communicates with its peer over
+// conn and should close conn once it is done sending messages.
+type Actor func(ctx context.Context, conn *Conn) error
+
+// MITM is an active attacker sitting between two actors. Messages sent by
+// either actor arrive at the MITM, which decides what, if anything, to pass
+// along to the other.
+type MITM func(ctx context.Context, alice, bob *Conn) error
+
+// Run runs alice and bob concurrently until both return. If mallory is not
+// nil, alice and bob are each connected to mallory rather than to each other.
+// The first error returned by any participant cancels the context passed to
+// the others and is returned by Run.
+func Run(ctx context.Context, alice, bob Actor, mallory MITM) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+
+	run := func(name string, f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := f(); err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("%s: %w", name, err)
+					cancel()
+				})
+			}
+		}()
+	}
+
+	if mallory == nil {
+		aliceConn, bobConn := Pipe()
+		run("alice", func() error { return alice(ctx, aliceConn) })
+		run("bob", func() error { return bob(ctx, bobConn) })
+	} else {
+		aliceConn, malloryAliceConn := Pipe()
+		bobConn, malloryBobConn := Pipe()
+		run("alice", func() error { return alice(ctx, aliceConn) })
+		run("bob", func() error { return bob(ctx, bobConn) })
+		run("mallory", func() error { return mallory(ctx, malloryAliceConn, malloryBobConn) })
+	}
+
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,122 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRun_DirectConnection(t *testing.T) {
+	var got []string
+	alice := func(ctx context.Context, conn *Conn) error {
+		defer conn.Close()
+		for _, msg := range []string{"hello", "world"} {
+			if err := conn.Send(ctx, msg); err != nil {
+				return err
+			}
+			echo, err := Receive[string](ctx, conn)
+			if err != nil {
+				return err
+			}
+			got = append(got, echo)
+		}
+		return nil
+	}
+
+	err := Run(context.Background(), alice, echo, nil)
+	if err != nil {
+		t.Fatalf("running protocol: %v", err)
+	}
+
+	if want := "hello,world"; want != strings.Join(got, ",") {
+		t.Fatalf("want: '%s', got: '%s'", want, strings.Join(got, ","))
+	}
+}
+
+func TestRun_MITMCanTamperWithMessages(t *testing.T) {
+	var got string
+	alice := func(ctx context.Context, conn *Conn) error {
+		defer conn.Close()
+		if err := conn.Send(ctx, "hello"); err != nil {
+			return err
+		}
+		var err error
+		got, err = Receive[string](ctx, conn)
+		return err
+	}
+	mallory := func(ctx context.Context, alice, bob *Conn) error {
+		defer alice.Close()
+		defer bob.Close()
+		msg, err := Receive[string](ctx, alice)
+		if err != nil {
+			return err
+		}
+		if err := bob.Send(ctx, strings.ToUpper(msg)); err != nil {
+			return err
+		}
+		echo, err := bob.Recv(ctx)
+		if err != nil {
+			return err
+		}
+		return alice.Send(ctx, echo)
+	}
+
+	err := Run(context.Background(), alice, echo, mallory)
+	if err != nil {
+		t.Fatalf("running protocol: %v", err)
+	}
+
+	if want := "HELLO"; want != got {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+}
+
+func TestRun_ErrorCancelsOtherParticipants(t *testing.T) {
+	errBoom := errors.New("boom")
+	alice := func(ctx context.Context, conn *Conn) error {
+		return errBoom
+	}
+	bob := func(ctx context.Context, conn *Conn) error {
+		_, err := conn.Recv(ctx)
+		return err
+	}
+
+	err := Run(context.Background(), alice, bob, nil)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("want: %v, got: %v", errBoom, err)
+	}
+}
+
+func TestReceive_UnexpectedType(t *testing.T) {
+	alice := func(ctx context.Context, conn *Conn) error {
+		defer conn.Close()
+		return conn.Send(ctx, 42)
+	}
+	bob := func(ctx context.Context, conn *Conn) error {
+		_, err := Receive[string](ctx, conn)
+		return err
+	}
+
+	if err := Run(context.Background(), alice, bob, nil); err == nil {
+		t.Fatal("want error, got nil")
+	}
+}
+
+// echo sends back every message it receives until the connection is closed.
+func echo(ctx context.Context, conn *Conn) error {
+	defer conn.Close()
+	for {
+		msg, err := conn.Recv(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := conn.Send(ctx, msg); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package rsa
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/saclark/cryptopals/sha1"
+)
+
+var (
+	// ErrVerification is returned when a signature fails to verify.
+	ErrVerification = errors.New("rsa: verification error")
+	// ErrDecryption is returned when a ciphertext does not decrypt to a
+	// properly padded message.
+	ErrDecryption = errors.New("rsa: decryption error")
+)
+
+// sha1DigestInfoPrefix is the DER encoding of an ASN.1 DigestInfo with the
+// SHA-1 AlgorithmIdentifier, up to but not including the digest itself.
+var sha1DigestInfoPrefix = []byte{
+	0x30, 0x21, 0x30, 0x09, 0x06, 0x05, 0x2b, 0x0e,
+	0x03, 0x02, 0x1a, 0x05, 0x00, 0x04, 0x14,
+}
+
+// SHA1DigestInfo returns the DER encoded ASN.1 DigestInfo of the SHA-1 digest
+// of message, which is what PKCS#1 v1.5 signatures sign.
+func SHA1DigestInfo(message []byte) []byte {
+	digest := sha1.Sum(message)
+	t := make([]byte, 0, len(sha1DigestInfoPrefix)+sha1.Size)
+	t = append(t, sha1DigestInfoPrefix...)
+	return append(t, digest[:]...)
+}
+
+// SignPKCS1v15 returns the RSASSA-PKCS1-v1_5 signature of the SHA-1 digest of
+// message. The signature is exactly priv.Size() bytes long.
+//
+// A proper implementation exists in crypto/rsa. This was written as a learning
+// exercise.
+func SignPKCS1v15(priv *PrivateKey, message []byte) ([]byte, error) {
+	em, err := encodePKCS1v15Signature(priv.Size(), message)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Decrypt(priv, BytesToInt(em))
+	if err != nil {
+		return nil, err
+	}
+	return IntToBytes(s, priv.Size()), nil
+}
+
+// VerifyPKCS1v15 verifies an RSASSA-PKCS1-v1_5 signature of the SHA-1 digest of
+// message. It returns ErrVerification if the signature is not valid.
+func VerifyPKCS1v15(pub *PublicKey, message, sig []byte) error {
+	em, err := openPKCS1v15Signature(pub, sig)
+	if err != nil {
+		return err
+	}
+	want, err := encodePKCS1v15Signature(pub.Size(), message)
+	if err != nil {
+		return ErrVerification
+	}
+	if subtle.ConstantTimeCompare(em, want) != 1 {
+		return ErrVerification
+	}
+	return nil
+}
+
+// VerifyPKCS1v15Sloppy is like VerifyPKCS1v15 but, like many verifiers once
+// did, it parses the encoded message from left to right instead of checking
+// its full length. It skips over any number of 0xff padding bytes and ignores
+// whatever follows the digest, allowing for the Bleichenbacher e=3 signature
+// forgery. Do not use it.
+func VerifyPKCS1v15Sloppy(pub *PublicKey, message, sig []byte) error {
+	em, err := openPKCS1v15Signature(pub, sig)
+	if err != nil {
+		return err
+	}
+	if em[0] != 0x00 || em[1] != 0x01 {
+		return ErrVerification
+	}
+
+	i := 2
+	for i < len(em) && em[i] == 0xff {
+		i++
+	}
+	if i == 2 || i == len(em) || em[i] != 0x00 {
+		return ErrVerification
+	}
+	i++
+
+	t := SHA1DigestInfo(message)
+	if len(em)-i < len(t) || subtle.ConstantTimeCompare(em[i:i+len(t)], t) != 1 {
+		return ErrVerification
+	}
+	return nil
+}
+
+// encodePKCS1v15Signature returns the k byte encoded message
+// 0x00 || 0x01 || 0xff... || 0x00 || DigestInfo.
+func encodePKCS1v15Signature(k int, message []byte) ([]byte, error) {
+	t := SHA1DigestInfo(message)
+	if k < len(t)+11 {
+		return nil, ErrMessageTooLong
+	}
+
+	em := make([]byte, k)
+	em[1] = 0x01
+	for i := 2; i < k-len(t)-1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[k-len(t):], t)
+	return em, nil
+}
+
+// openPKCS1v15Signature returns the encoded message that sig is a signature
+// of, left padded to pub.Size() bytes.
+func openPKCS1v15Signature(pub *PublicKey, sig []byte) ([]byte, error) {
+	k := pub.Size()
+	if len(sig) != k || k < len(sha1DigestInfoPrefix)+sha1.Size+11 {
+		return nil, ErrVerification
+	}
+	m, err := Encrypt(pub, BytesToInt(sig))
+	if err != nil {
+		return nil, ErrVerification
+	}
+	return IntToBytes(m, k), nil
+}
+
+// EncryptPKCS1v15 encrypts message with RSAES-PKCS1-v1_5 padding, reading the
+// random, non-zero padding bytes from rand. The ciphertext is exactly
+// pub.Size() bytes long. It returns ErrMessageTooLong if message is longer
+// than pub.Size()-11 bytes.
+//
+// A proper implementation exists in crypto/rsa. This was written as a learning
+// exercise.
+func EncryptPKCS1v15(rand io.Reader, pub *PublicKey, message []byte) ([]byte, error) {
+	k := pub.Size()
+	if len(message) > k-11 {
+		return nil, ErrMessageTooLong
+	}
+
+	// em = 0x00 || 0x02 || PS || 0x00 || message
+	em := make([]byte, k)
+	em[1] = 0x02
+	ps := em[2 : k-len(message)-1]
+	if err := nonZeroRandomBytes(rand, ps); err != nil {
+		return nil, fmt.Errorf("generating padding: %w", err)
+	}
+	copy(em[k-len(message):], message)
+
+	c, err := Encrypt(pub, BytesToInt(em))
+	if err != nil {
+		return nil, err
+	}
+	return IntToBytes(c, k), nil
+}
+
+// DecryptPKCS1v15 decrypts an RSAES-PKCS1-v1_5 ciphertext and removes its
+// padding. It returns ErrDecryption if the ciphertext is not valid.
+//
+// Reporting why a ciphertext was not valid, or taking measurably different
+// amounts of time to reject different ciphertexts, provides an attacker with a
+// padding oracle. This implementation makes no effort to avoid the latter.
+func DecryptPKCS1v15(priv *PrivateKey, ciphertext []byte) ([]byte, error) {
+	k := priv.Size()
+	if len(ciphertext) != k || k < 11 {
+		return nil, ErrDecryption
+	}
+	m, err := Decrypt(priv, BytesToInt(ciphertext))
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	em := IntToBytes(m, k)
+	if em[0] != 0x00 || em[1] != 0x02 {
+		return nil, ErrDecryption
+	}
+	i := 2
+	for i < len(em) && em[i] != 0x00 {
+		i++
+	}
+	if i == len(em) || i < 10 {
+		return nil, ErrDecryption
+	}
+
+	return em[i+1:], nil
+}
+
+// nonZeroRandomBytes fills b with random bytes read from rand, none of which
+// are zero.
+func nonZeroRandomBytes(rand io.Reader, b []byte) error {
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return err
+	}
+	for i := range b {
+		for b[i] == 0 {
+			if _, err := io.ReadFull(rand, b[i:i+1]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
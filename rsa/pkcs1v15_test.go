@@ -0,0 +1,169 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	stdrsa "crypto/rsa"
+	stdsha1 "crypto/sha1"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestSignPKCS1v15_MatchesStdLib(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader, 1024, 65537)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	stdPriv := &stdrsa.PrivateKey{
+		PublicKey: stdrsa.PublicKey{N: priv.N, E: priv.E},
+		D:         priv.D,
+		Primes:    []*big.Int{priv.P, priv.Q},
+	}
+	stdPriv.Precompute()
+
+	message := []byte("hi mom")
+	digest := stdsha1.Sum(message)
+	want, err := stdrsa.SignPKCS1v15(nil, stdPriv, crypto.SHA1, digest[:])
+	if err != nil {
+		t.Fatalf("signing with crypto/rsa: %v", err)
+	}
+
+	got, err := SignPKCS1v15(priv, message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%x', got: '%x'", want, got)
+	}
+}
+
+func TestVerifyPKCS1v15(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader, 1024, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	message := []byte("hi mom")
+	sig, err := SignPKCS1v15(priv, message)
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+
+	verifiers := map[string]func(*PublicKey, []byte, []byte) error{
+		"strict": VerifyPKCS1v15,
+		"sloppy": VerifyPKCS1v15Sloppy,
+	}
+	for name, verify := range verifiers {
+		t.Run(name, func(t *testing.T) {
+			if err := verify(&priv.PublicKey, message, sig); err != nil {
+				t.Fatalf("verifying valid signature: %v", err)
+			}
+			if err := verify(&priv.PublicKey, []byte("hi dad"), sig); !errors.Is(err, ErrVerification) {
+				t.Fatalf("verifying signature of wrong message: want: %v, got: %v", ErrVerification, err)
+			}
+			tampered := bytes.Clone(sig)
+			tampered[len(tampered)-1] ^= 1
+			if err := verify(&priv.PublicKey, message, tampered); !errors.Is(err, ErrVerification) {
+				t.Fatalf("verifying tampered signature: want: %v, got: %v", ErrVerification, err)
+			}
+			if err := verify(&priv.PublicKey, message, sig[1:]); !errors.Is(err, ErrVerification) {
+				t.Fatalf("verifying truncated signature: want: %v, got: %v", ErrVerification, err)
+			}
+		})
+	}
+}
+
+func TestVerifyPKCS1v15_RejectsTrailingGarbage(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader, 1024, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	// Sign a short padding string followed by the DigestInfo and garbage.
+	message := []byte("hi mom")
+	em := make([]byte, priv.Size())
+	em[1], em[2], em[3] = 0x01, 0xff, 0x00
+	copy(em[4:], SHA1DigestInfo(message))
+	s, err := Decrypt(priv, BytesToInt(em))
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	sig := IntToBytes(s, priv.Size())
+
+	if err := VerifyPKCS1v15(&priv.PublicKey, message, sig); !errors.Is(err, ErrVerification) {
+		t.Fatalf("strict: want: %v, got: %v", ErrVerification, err)
+	}
+	if err := VerifyPKCS1v15Sloppy(&priv.PublicKey, message, sig); err != nil {
+		t.Fatalf("sloppy: want: nil, got: %v", err)
+	}
+}
+
+func TestEncryptPKCS1v15_InteroperatesWithStdLib(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader, 1024, 65537)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	stdPriv := &stdrsa.PrivateKey{
+		PublicKey: stdrsa.PublicKey{N: priv.N, E: priv.E},
+		D:         priv.D,
+		Primes:    []*big.Int{priv.P, priv.Q},
+	}
+	stdPriv.Precompute()
+	want := []byte("kick it, CC")
+
+	ciphertext, err := EncryptPKCS1v15(rand.Reader, &priv.PublicKey, want)
+	if err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	got, err := stdrsa.DecryptPKCS1v15(nil, stdPriv, ciphertext)
+	if err != nil {
+		t.Fatalf("decrypting with crypto/rsa: %v", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+
+	ciphertext, err = stdrsa.EncryptPKCS1v15(rand.Reader, &stdPriv.PublicKey, want)
+	if err != nil {
+		t.Fatalf("encrypting with crypto/rsa: %v", err)
+	}
+	got, err = DecryptPKCS1v15(priv, ciphertext)
+	if err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+}
+
+func TestDecryptPKCS1v15_InvalidPadding(t *testing.T) {
+	priv, err := GenerateKey(rand.Reader, 512, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	tt := map[string][]byte{
+		"wrong block type":  {0x00, 0x01, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 'h', 'i'},
+		"short padding":     {0x00, 0x02, 0xff, 0xff, 0xff, 0x00, 'h', 'i'},
+		"missing separator": {0x00, 0x02, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 'h', 'i'},
+	}
+	for name, prefix := range tt {
+		t.Run(name, func(t *testing.T) {
+			em := bytes.Repeat([]byte{0xff}, priv.Size())
+			copy(em, prefix)
+			if name != "missing separator" {
+				copy(em[len(prefix):], bytes.Repeat([]byte{'!'}, len(em)-len(prefix)))
+			}
+			c, err := Encrypt(&priv.PublicKey, BytesToInt(em))
+			if err != nil {
+				t.Fatalf("encrypting: %v", err)
+			}
+			if _, err := DecryptPKCS1v15(priv, IntToBytes(c, priv.Size())); !errors.Is(err, ErrDecryption) {
+				t.Fatalf("want: %v, got: %v", ErrDecryption, err)
+			}
+		})
+	}
+}
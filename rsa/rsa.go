@@ -0,0 +1,192 @@
+package rsa
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	// ErrNoInverse is returned by InvMod when its arguments are not coprime.
+	ErrNoInverse = errors.New("rsa: no modular inverse")
+	// ErrMessageTooLong is returned when a message is not less than the
+	// modulus.
+	ErrMessageTooLong = errors.New("rsa: message too long for RSA key size")
+)
+
+// PublicKey is a textbook RSA public key.
+type PublicKey struct {
+	N *big.Int
+	E int
+}
+
+// Size returns the modulus size in bytes.
+func (pub *PublicKey) Size() int {
+	return (pub.N.BitLen() + 7) / 8
+}
+
+// PrivateKey is a textbook RSA private key.
+type PrivateKey struct {
+	PublicKey
+	D *big.Int
+	P *big.Int
+	Q *big.Int
+}
+
+// NewPrivateKey returns the private key with public exponent e for the primes
+// p and q. It returns ErrNoInverse if e is not invertible mod (p-1)(q-1).
+func NewPrivateKey(p, q *big.Int, e int) (*PrivateKey, error) {
+	one := big.NewInt(1)
+	pMinusOne := new(big.Int).Sub(p, one)
+	qMinusOne := new(big.Int).Sub(q, one)
+	totient := new(big.Int).Mul(pMinusOne, qMinusOne)
+
+	d, err := InvMod(big.NewInt(int64(e)), totient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PrivateKey{
+		PublicKey: PublicKey{N: new(big.Int).Mul(p, q), E: e},
+		D:         d,
+		P:         p,
+		Q:         q,
+	}, nil
+}
+
+// GenerateKey generates a private key with a modulus of the given bit size and
+// public exponent e, typically 3 or 65537, reading random bytes from rand,
+// which will typically be crypto/rand.Reader. Primes are regenerated until e is
+// invertible mod the totient.
+func GenerateKey(rand io.Reader, bits, e int) (*PrivateKey, error) {
+	if bits < 16 {
+		return nil, errors.New("rsa: key size too small")
+	}
+	if e < 3 || e%2 == 0 {
+		return nil, errors.New("rsa: public exponent must be odd and >= 3")
+	}
+
+	for {
+		p, err := GeneratePrime(rand, bits-bits/2)
+		if err != nil {
+			return nil, fmt.Errorf("generating p: %w", err)
+		}
+		q, err := GeneratePrime(rand, bits/2)
+		if err != nil {
+			return nil, fmt.Errorf("generating q: %w", err)
+		}
+		if p.Cmp(q) == 0 {
+			continue
+		}
+
+		priv, err := NewPrivateKey(p, q, e)
+		if errors.Is(err, ErrNoInverse) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if priv.N.BitLen() != bits {
+			continue
+		}
+
+		return priv, nil
+	}
+}
+
+// GeneratePrime returns a random prime of exactly the given bit size, reading
+// random bytes from rand. The top two bits are always set so the product of
+// two such primes has exactly twice as many bits.
+//
+// A proper implementation exists in crypto/rand. This was written as a learning
+// exercise.
+func GeneratePrime(rand io.Reader, bits int) (*big.Int, error) {
+	if bits < 2 {
+		return nil, errors.New("rsa: prime size must be at least 2-bit")
+	}
+
+	b := make([]byte, (bits+7)/8)
+	excess := uint(len(b)*8 - bits)
+	p := new(big.Int)
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, fmt.Errorf("reading random bytes: %w", err)
+		}
+
+		// Clear the bits above the requested size, then set the top two bits
+		// and make the candidate odd.
+		b[0] &= byte(0xff >> excess)
+		p.SetBytes(b)
+		p.SetBit(p, bits-1, 1)
+		p.SetBit(p, bits-2, 1)
+		p.SetBit(p, 0, 1)
+
+		if p.ProbablyPrime(20) {
+			return p, nil
+		}
+	}
+}
+
+// InvMod returns the multiplicative inverse of a mod m using the extended
+// Euclidean algorithm. It returns ErrNoInverse if a and m are not coprime.
+//
+// A proper implementation exists in math/big. This was written as a learning
+// exercise.
+func InvMod(a, m *big.Int) (*big.Int, error) {
+	if m.Sign() <= 0 {
+		return nil, ErrNoInverse
+	}
+
+	// Maintain the invariants oldR = oldS*a (mod m) and r = s*a (mod m).
+	oldR, r := new(big.Int).Mod(a, m), new(big.Int).Set(m)
+	oldS, s := big.NewInt(1), big.NewInt(0)
+	q := new(big.Int)
+	for r.Sign() != 0 {
+		q.Quo(oldR, r)
+		oldR, r = r, new(big.Int).Sub(oldR, new(big.Int).Mul(q, r))
+		oldS, s = s, new(big.Int).Sub(oldS, new(big.Int).Mul(q, s))
+	}
+
+	// oldR is now gcd(a, m).
+	if oldR.Cmp(big.NewInt(1)) != 0 {
+		return nil, ErrNoInverse
+	}
+	return oldS.Mod(oldS, m), nil
+}
+
+// Encrypt returns m^e mod N. It returns ErrMessageTooLong if m is not in
+// [0, N).
+func Encrypt(pub *PublicKey, m *big.Int) (*big.Int, error) {
+	if m.Sign() < 0 || m.Cmp(pub.N) >= 0 {
+		return nil, ErrMessageTooLong
+	}
+	return new(big.Int).Exp(m, big.NewInt(int64(pub.E)), pub.N), nil
+}
+
+// Decrypt returns c^d mod N. It returns ErrMessageTooLong if c is not in
+// [0, N).
+func Decrypt(priv *PrivateKey, c *big.Int) (*big.Int, error) {
+	if c.Sign() < 0 || c.Cmp(priv.N) >= 0 {
+		return nil, ErrMessageTooLong
+	}
+	return new(big.Int).Exp(c, priv.D, priv.N), nil
+}
+
+// BytesToInt interprets b as a big-endian unsigned integer.
+func BytesToInt(b []byte) *big.Int {
+	return new(big.Int).SetBytes(b)
+}
+
+// IntToBytes returns the big-endian bytes of x, left padded with zeros to size
+// bytes. If x does not fit in size bytes, its minimal big-endian encoding is
+// returned instead.
+func IntToBytes(x *big.Int, size int) []byte {
+	b := x.Bytes()
+	if len(b) >= size {
+		return b
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(b):], b)
+	return padded
+}
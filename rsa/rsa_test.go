@@ -0,0 +1,152 @@
+package rsa
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestInvMod(t *testing.T) {
+	tt := []struct {
+		a, m, want int64
+	}{
+		{17, 3120, 2753},
+		{3, 11, 4},
+		{10, 17, 12},
+		{1, 7, 1},
+		{-3, 11, 7},
+		{14, 11, 4},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("invmod(%d,%d)", tc.a, tc.m), func(t *testing.T) {
+			got, err := InvMod(big.NewInt(tc.a), big.NewInt(tc.m))
+			if err != nil {
+				t.Fatalf("want: %d, got error: %v", tc.want, err)
+			}
+			if got.Cmp(big.NewInt(tc.want)) != 0 {
+				t.Fatalf("want: %d, got: %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestInvMod_MatchesStdLib(t *testing.T) {
+	m, err := GeneratePrime(rand.Reader, 256)
+	if err != nil {
+		t.Fatalf("generating prime: %v", err)
+	}
+	for i := 0; i < 100; i++ {
+		a, err := rand.Int(rand.Reader, m)
+		if err != nil {
+			t.Fatalf("generating random int: %v", err)
+		}
+		if a.Sign() == 0 {
+			continue
+		}
+		want := new(big.Int).ModInverse(a, m)
+		got, err := InvMod(a, m)
+		if err != nil {
+			t.Fatalf("want: %d, got error: %v", want, err)
+		}
+		if want.Cmp(got) != 0 {
+			t.Fatalf("want: %d, got: %d", want, got)
+		}
+	}
+}
+
+func TestInvMod_NotCoprime(t *testing.T) {
+	_, err := InvMod(big.NewInt(6), big.NewInt(9))
+	if !errors.Is(err, ErrNoInverse) {
+		t.Fatalf("want: %v, got: %v", ErrNoInverse, err)
+	}
+}
+
+func TestGeneratePrime(t *testing.T) {
+	for _, bits := range []int{2, 3, 8, 13, 64, 512} {
+		p, err := GeneratePrime(rand.Reader, bits)
+		if err != nil {
+			t.Fatalf("%d bits: generating prime: %v", bits, err)
+		}
+		if p.BitLen() != bits {
+			t.Errorf("%d bits: got %d bit prime", bits, p.BitLen())
+		}
+		if !p.ProbablyPrime(20) {
+			t.Errorf("%d bits: %d is not prime", bits, p)
+		}
+	}
+}
+
+func TestEncryptThenDecrypt(t *testing.T) {
+	tt := []struct {
+		bits int
+		e    int
+	}{
+		{64, 3},
+		{1024, 3},
+		{1024, 65537},
+		{2048, 3},
+	}
+
+	for _, tc := range tt {
+		t.Run(fmt.Sprintf("%d bits,e=%d", tc.bits, tc.e), func(t *testing.T) {
+			priv, err := GenerateKey(rand.Reader, tc.bits, tc.e)
+			if err != nil {
+				t.Fatalf("generating key: %v", err)
+			}
+			if priv.N.BitLen() != tc.bits {
+				t.Fatalf("want %d bit modulus, got %d", tc.bits, priv.N.BitLen())
+			}
+
+			m := big.NewInt(42)
+			c, err := Encrypt(&priv.PublicKey, m)
+			if err != nil {
+				t.Fatalf("encrypting: %v", err)
+			}
+			got, err := Decrypt(priv, c)
+			if err != nil {
+				t.Fatalf("decrypting: %v", err)
+			}
+			if m.Cmp(got) != 0 {
+				t.Fatalf("want: %d, got: %d", m, got)
+			}
+		})
+	}
+}
+
+func TestEncrypt_MessageTooLong(t *testing.T) {
+	priv, err := NewPrivateKey(big.NewInt(61), big.NewInt(53), 17)
+	if err != nil {
+		t.Fatalf("creating key: %v", err)
+	}
+	_, err = Encrypt(&priv.PublicKey, priv.N)
+	if !errors.Is(err, ErrMessageTooLong) {
+		t.Fatalf("want: %v, got: %v", ErrMessageTooLong, err)
+	}
+}
+
+func TestIntToBytes(t *testing.T) {
+	tt := []struct {
+		x    int64
+		size int
+		want []byte
+	}{
+		{0, 0, []byte{}},
+		{0, 2, []byte{0x00, 0x00}},
+		{0x0102, 4, []byte{0x00, 0x00, 0x01, 0x02}},
+		{0x0102, 1, []byte{0x01, 0x02}},
+	}
+
+	for _, tc := range tt {
+		got := IntToBytes(big.NewInt(tc.x), tc.size)
+		if !bytes.Equal(tc.want, got) {
+			t.Errorf("want: '%x', got: '%x'", tc.want, got)
+		}
+		if BytesToInt(got).Cmp(big.NewInt(tc.x)) != 0 {
+			t.Errorf("want: %d, got: %d", tc.x, BytesToInt(got))
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package set5
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/dh"
+)
+
+func TestChallenge33(t *testing.T) {
+	tt := []struct {
+		name  string
+		group *dh.Group
+	}{
+		{"p=37,g=5", &dh.Group{P: big.NewInt(37), G: big.NewInt(5)}},
+		{"NIST", dh.NISTGroup()},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			aliceKey, bobKey, err := ExchangeDHKeys(tc.group)
+			if err != nil {
+				t.Fatalf("exchanging keys: %v", err)
+			}
+			if !bytes.Equal(aliceKey, bobKey) {
+				t.Fatalf("want: '%x', got: '%x'", aliceKey, bobKey)
+			}
+		})
+	}
+}
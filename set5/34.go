@@ -0,0 +1,284 @@
+// # Implement a MITM key-fixing attack on Diffie-Hellman with parameter injection
+//
+// Use the code you just worked out to build a protocol and an "echo" bot. You
+// don't actually have to do the network part of this if you don't want; just
+// simulate that. The protocol is:
+//
+//	A->B
+//	    Send "p", "g", "A"
+//	B->A
+//	    Send "B"
+//	A->B
+//	    Send AES-CBC(SHA1(s)[0:16], iv=random(16), msg) + iv
+//	B->A
+//	    Send AES-CBC(SHA1(s)[0:16], iv=random(16), A's msg) + iv
+//
+// (In other words, derive an AES key from DH with SHA1, use it in both
+// directions, and do CBC with random IVs appended or prepended to the message).
+//
+// Now implement the following MITM attack:
+//
+//	A->M
+//	    Send "p", "g", "A"
+//	M->B
+//	    Send "p", "g", "p"
+//	B->M
+//	    Send "B"
+//	M->A
+//	    Send "p"
+//	A->M
+//	    Send AES-CBC(SHA1(s)[0:16], iv=random(16), msg) + iv
+//	M->B
+//	    Relay that to B
+//	B->M
+//	    Send AES-CBC(SHA1(s)[0:16], iv=random(16), A's msg) + iv
+//	M->A
+//	    Relay that to A
+//
+// M should be able to decrypt the messages. "A" and "B" in the protocol --- the
+// public keys, over the wire --- have been swapped out with "p". Do the DH math
+// on this quickly to see what that does to the predictability of the key.
+//
+// Decrypt the messages from M's vantage point as they go by.
+//
+// Note that you don't actually have to inject bogus parameters to make this
+// attack work; you could just generate Ma, MA, Mb, and MB as valid DH
+// parameters to do a generic MITM attack. But do the parameter injection
+// attack; it's going to come up again.
+
+package set5
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/saclark/cryptopals/cipher"
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/pkcs7"
+	"github.com/saclark/cryptopals/protocol"
+)
+
+// DHEchoHello is sent by the client to begin the echo protocol.
+type DHEchoHello struct {
+	P, G, A *big.Int
+}
+
+// DHEchoHelloReply is sent by the server in response to a DHEchoHello.
+type DHEchoHelloReply struct {
+	B *big.Int
+}
+
+// EncryptedMessage is an AES-CBC encrypted, PKCS#7 padded message along with
+// the random IV it was encrypted under.
+type EncryptedMessage struct {
+	Ciphertext []byte
+	IV         []byte
+}
+
+// NewDHEchoClient returns an actor (A) that negotiates a key with its peer,
+// sends each of messages and verifies that each is echoed back.
+func NewDHEchoClient(group *dh.Group, messages [][]byte) protocol.Actor {
+	return func(ctx context.Context, conn *protocol.Conn) error {
+		defer conn.Close()
+
+		priv, err := dh.GenerateKey(group, rand.Reader)
+		if err != nil {
+			return fmt.Errorf("generating key: %w", err)
+		}
+
+		hello := DHEchoHello{P: group.P, G: group.G, A: priv.Y}
+		if err := conn.Send(ctx, hello); err != nil {
+			return fmt.Errorf("sending hello: %w", err)
+		}
+
+		reply, err := protocol.Receive[DHEchoHelloReply](ctx, conn)
+		if err != nil {
+			return fmt.Errorf("receiving hello reply: %w", err)
+		}
+
+		key := dh.DeriveKey(priv.SharedSecret(reply.B))
+		for _, msg := range messages {
+			if err := sendEncrypted(ctx, conn, key, msg); err != nil {
+				return err
+			}
+			echo, err := receiveEncrypted(ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(msg, echo) {
+				return fmt.Errorf("echo mismatch: want '%s', got '%s'", msg, echo)
+			}
+		}
+
+		return nil
+	}
+}
+
+// DHEchoServer is an actor (B) that negotiates a key with its peer and then
+// echoes back every message it receives, re-encrypted under a fresh IV, until
+// the connection is closed.
+func DHEchoServer(ctx context.Context, conn *protocol.Conn) error {
+	defer conn.Close()
+
+	hello, err := protocol.Receive[DHEchoHello](ctx, conn)
+	if err != nil {
+		return fmt.Errorf("receiving hello: %w", err)
+	}
+
+	priv, err := dh.GenerateKey(&dh.Group{P: hello.P, G: hello.G}, rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	if err := conn.Send(ctx, DHEchoHelloReply{B: priv.Y}); err != nil {
+		return fmt.Errorf("sending hello reply: %w", err)
+	}
+
+	key := dh.DeriveKey(priv.SharedSecret(hello.A))
+	return echoEncrypted(ctx, conn, key)
+}
+
+// DHKeyFixingMITM implements the parameter injection attack on the echo
+// protocol. Replacing both public keys with p means both parties compute
+// s = p^x mod p = 0, so the key is just SHA1(0)[0:16]. Every message relayed
+// between the two parties is decrypted and stored in Plaintexts, in the order
+// they were sent.
+type DHKeyFixingMITM struct {
+	Plaintexts [][]byte
+}
+
+// Intercept acts as the MITM (M).
+func (m *DHKeyFixingMITM) Intercept(ctx context.Context, alice, bob *protocol.Conn) error {
+	defer alice.Close()
+	defer bob.Close()
+
+	hello, err := protocol.Receive[DHEchoHello](ctx, alice)
+	if err != nil {
+		return fmt.Errorf("receiving hello: %w", err)
+	}
+	if err := bob.Send(ctx, DHEchoHello{P: hello.P, G: hello.G, A: hello.P}); err != nil {
+		return fmt.Errorf("sending hello: %w", err)
+	}
+
+	if _, err := protocol.Receive[DHEchoHelloReply](ctx, bob); err != nil {
+		return fmt.Errorf("receiving hello reply: %w", err)
+	}
+	if err := alice.Send(ctx, DHEchoHelloReply{B: hello.P}); err != nil {
+		return fmt.Errorf("sending hello reply: %w", err)
+	}
+
+	key := dh.DeriveKey(big.NewInt(0))
+	return relayEncrypted(ctx, alice, bob, func(msg EncryptedMessage) error {
+		plaintext, err := decryptMessage(key, msg)
+		if err != nil {
+			return err
+		}
+		m.Plaintexts = append(m.Plaintexts, plaintext)
+		return nil
+	})
+}
+
+// relayEncrypted passes each message from alice to bob and each of bob's
+// replies back to alice, calling inspect on every message as it goes by, until
+// alice closes the connection.
+func relayEncrypted(
+	ctx context.Context,
+	alice, bob *protocol.Conn,
+	inspect func(EncryptedMessage) error,
+) error {
+	for {
+		msg, err := protocol.Receive[EncryptedMessage](ctx, alice)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("receiving from alice: %w", err)
+		}
+		if err := inspect(msg); err != nil {
+			return fmt.Errorf("inspecting message from alice: %w", err)
+		}
+		if err := bob.Send(ctx, msg); err != nil {
+			return fmt.Errorf("relaying to bob: %w", err)
+		}
+
+		reply, err := protocol.Receive[EncryptedMessage](ctx, bob)
+		if err != nil {
+			return fmt.Errorf("receiving from bob: %w", err)
+		}
+		if err := inspect(reply); err != nil {
+			return fmt.Errorf("inspecting message from bob: %w", err)
+		}
+		if err := alice.Send(ctx, reply); err != nil {
+			return fmt.Errorf("relaying to alice: %w", err)
+		}
+	}
+}
+
+// echoEncrypted decrypts each message received on conn and sends it back
+// re-encrypted under a fresh IV until the connection is closed.
+func echoEncrypted(ctx context.Context, conn *protocol.Conn, key []byte) error {
+	for {
+		msg, err := receiveEncrypted(ctx, conn, key)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := sendEncrypted(ctx, conn, key, msg); err != nil {
+			return err
+		}
+	}
+}
+
+func sendEncrypted(ctx context.Context, conn *protocol.Conn, key, plaintext []byte) error {
+	msg, err := encryptMessage(key, plaintext)
+	if err != nil {
+		return err
+	}
+	if err := conn.Send(ctx, msg); err != nil {
+		return fmt.Errorf("sending encrypted message: %w", err)
+	}
+	return nil
+}
+
+func receiveEncrypted(ctx context.Context, conn *protocol.Conn, key []byte) ([]byte, error) {
+	msg, err := protocol.Receive[EncryptedMessage](ctx, conn)
+	if err == io.EOF {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("receiving encrypted message: %w", err)
+	}
+	return decryptMessage(key, msg)
+}
+
+func encryptMessage(key, plaintext []byte) (EncryptedMessage, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return EncryptedMessage{}, fmt.Errorf("generating IV: %w", err)
+	}
+	padded := pkcs7.Pad(bytes.Clone(plaintext), aes.BlockSize)
+	ciphertext, err := cipher.CBCEncrypt(padded, key, iv)
+	if err != nil {
+		return EncryptedMessage{}, fmt.Errorf("AES-CBC encrypting: %w", err)
+	}
+	return EncryptedMessage{Ciphertext: ciphertext, IV: iv}, nil
+}
+
+func decryptMessage(key []byte, msg EncryptedMessage) ([]byte, error) {
+	plaintext, err := cipher.CBCDecrypt(msg.Ciphertext, key, msg.IV)
+	if err != nil {
+		return nil, fmt.Errorf("AES-CBC decrypting: %w", err)
+	}
+	plaintext, err = pkcs7.Unpad(plaintext, aes.BlockSize)
+	if err != nil {
+		return nil, fmt.Errorf("removing PKCS#7 padding: %w", err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,48 @@
+package set5
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/protocol"
+)
+
+var challenge34Messages = [][]byte{
+	[]byte("Rollin' in my 5.0"),
+	[]byte("With my rag-top down so my hair can blow"),
+	[]byte("The girlies on standby waving just to say hi"),
+}
+
+func TestChallenge34_EchoProtocol(t *testing.T) {
+	client := NewDHEchoClient(dh.NISTGroup(), challenge34Messages)
+
+	if err := protocol.Run(context.Background(), client, DHEchoServer, nil); err != nil {
+		t.Fatalf("running echo protocol: %v", err)
+	}
+}
+
+func TestChallenge34_KeyFixingMITM(t *testing.T) {
+	client := NewDHEchoClient(dh.NISTGroup(), challenge34Messages)
+	mitm := &DHKeyFixingMITM{}
+
+	if err := protocol.Run(context.Background(), client, DHEchoServer, mitm.Intercept); err != nil {
+		t.Fatalf("running echo protocol: %v", err)
+	}
+
+	// Each message is seen twice: once on the way to B and once echoed back.
+	var want [][]byte
+	for _, msg := range challenge34Messages {
+		want = append(want, msg, msg)
+	}
+
+	if len(want) != len(mitm.Plaintexts) {
+		t.Fatalf("want %d plaintexts, got %d", len(want), len(mitm.Plaintexts))
+	}
+	for i := range want {
+		if !bytes.Equal(want[i], mitm.Plaintexts[i]) {
+			t.Errorf("[%d]: want: '%s', got: '%s'", i, want[i], mitm.Plaintexts[i])
+		}
+	}
+}
@@ -0,0 +1,284 @@
+// # Implement DH with negotiated groups, and break with malicious "g" parameters
+//
+//	A->B
+//	    Send "p", "g"
+//	B->A
+//	    Send ACK
+//	A->B
+//	    Send "A"
+//	B->A
+//	    Send "B"
+//	A->B
+//	    Send AES-CBC(SHA1(s)[0:16], iv=random(16), msg) + iv
+//	B->A
+//	    Send AES-CBC(SHA1(s)[0:16], iv=random(16), A's msg) + iv
+//
+// Do the MITM attack again, but play with "g". What happens with:
+//
+//	g = 1
+//	g = p
+//	g = p - 1
+//
+// Write attacks for each.
+//
+// > # When does this ever happen?
+// > Honestly, not that often in real-world systems. If you can mess with "g",
+// > chances are you can mess with something worse. Most systems pre-agree on a
+// > static DH group. But the same construction exists in Elliptic Curve
+// > Diffie-Hellman, and this becomes more relevant there.
+
+package set5
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/protocol"
+)
+
+// DHGroupProposal is sent by the client to propose a group.
+type DHGroupProposal struct {
+	P, G *big.Int
+}
+
+// DHGroupAck is sent by the server to acknowledge the group it will use. The
+// client adopts the acknowledged group.
+type DHGroupAck struct {
+	P, G *big.Int
+}
+
+// DHPublicKey is sent by each party once the group has been negotiated.
+type DHPublicKey struct {
+	Y *big.Int
+}
+
+// NewNegotiatedDHEchoClient returns an actor (A) that proposes group, uses
+// whichever group the server acknowledges to negotiate a key, sends each of
+// messages and verifies that each is echoed back.
+func NewNegotiatedDHEchoClient(group *dh.Group, messages [][]byte) protocol.Actor {
+	return func(ctx context.Context, conn *protocol.Conn) error {
+		defer conn.Close()
+
+		if err := conn.Send(ctx, DHGroupProposal{P: group.P, G: group.G}); err != nil {
+			return fmt.Errorf("sending group proposal: %w", err)
+		}
+
+		ack, err := protocol.Receive[DHGroupAck](ctx, conn)
+		if err != nil {
+			return fmt.Errorf("receiving group ack: %w", err)
+		}
+
+		priv, err := dh.GenerateKey(&dh.Group{P: ack.P, G: ack.G}, rand.Reader)
+		if err != nil {
+			return fmt.Errorf("generating key: %w", err)
+		}
+
+		if err := conn.Send(ctx, DHPublicKey{Y: priv.Y}); err != nil {
+			return fmt.Errorf("sending public key: %w", err)
+		}
+
+		peer, err := protocol.Receive[DHPublicKey](ctx, conn)
+		if err != nil {
+			return fmt.Errorf("receiving public key: %w", err)
+		}
+
+		key := dh.DeriveKey(priv.SharedSecret(peer.Y))
+		for _, msg := range messages {
+			if err := sendEncrypted(ctx, conn, key, msg); err != nil {
+				return err
+			}
+			echo, err := receiveEncrypted(ctx, conn, key)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(msg, echo) {
+				return fmt.Errorf("echo mismatch: want '%s', got '%s'", msg, echo)
+			}
+		}
+
+		return nil
+	}
+}
+
+// NegotiatedDHEchoServer is an actor (B) that accepts whatever group its peer
+// proposes, negotiates a key and then echoes back every message it receives,
+// re-encrypted under a fresh IV, until the connection is closed.
+func NegotiatedDHEchoServer(ctx context.Context, conn *protocol.Conn) error {
+	defer conn.Close()
+
+	proposal, err := protocol.Receive[DHGroupProposal](ctx, conn)
+	if err != nil {
+		return fmt.Errorf("receiving group proposal: %w", err)
+	}
+
+	if err := conn.Send(ctx, DHGroupAck(proposal)); err != nil {
+		return fmt.Errorf("sending group ack: %w", err)
+	}
+
+	peer, err := protocol.Receive[DHPublicKey](ctx, conn)
+	if err != nil {
+		return fmt.Errorf("receiving public key: %w", err)
+	}
+
+	priv, err := dh.GenerateKey(&dh.Group{P: proposal.P, G: proposal.G}, rand.Reader)
+	if err != nil {
+		return fmt.Errorf("generating key: %w", err)
+	}
+
+	if err := conn.Send(ctx, DHPublicKey{Y: priv.Y}); err != nil {
+		return fmt.Errorf("sending public key: %w", err)
+	}
+
+	key := dh.DeriveKey(priv.SharedSecret(peer.Y))
+	return echoEncrypted(ctx, conn, key)
+}
+
+// MaliciousG is a malicious generator substituted into a negotiated group.
+type MaliciousG int
+
+const (
+	GEqualsOne MaliciousG = iota
+	GEqualsP
+	GEqualsPMinusOne
+)
+
+func (g MaliciousG) String() string {
+	switch g {
+	case GEqualsOne:
+		return "g = 1"
+	case GEqualsP:
+		return "g = p"
+	case GEqualsPMinusOne:
+		return "g = p - 1"
+	default:
+		return fmt.Sprintf("MaliciousG(%d)", int(g))
+	}
+}
+
+// generator returns the malicious generator for modulus p.
+func (g MaliciousG) generator(p *big.Int) *big.Int {
+	switch g {
+	case GEqualsOne:
+		return big.NewInt(1)
+	case GEqualsP:
+		return new(big.Int).Set(p)
+	case GEqualsPMinusOne:
+		return new(big.Int).Sub(p, big.NewInt(1))
+	default:
+		panic("cryptopals/set5: unknown MaliciousG")
+	}
+}
+
+// candidateSecrets returns every shared secret that can result from
+// negotiating a key in a group with modulus p and the malicious generator:
+//
+//	g = 1:     s = 1^(ab) = 1
+//	g = p:     s = p^(ab) mod p = 0
+//	g = p - 1: s = (-1)^(ab) mod p = 1 if ab is even, otherwise p - 1
+func (g MaliciousG) candidateSecrets(p *big.Int) []*big.Int {
+	switch g {
+	case GEqualsOne:
+		return []*big.Int{big.NewInt(1)}
+	case GEqualsP:
+		return []*big.Int{big.NewInt(0)}
+	case GEqualsPMinusOne:
+		return []*big.Int{big.NewInt(1), new(big.Int).Sub(p, big.NewInt(1))}
+	default:
+		panic("cryptopals/set5: unknown MaliciousG")
+	}
+}
+
+// MaliciousGResult is the outcome of a MaliciousGMITM attack.
+type MaliciousGResult struct {
+	// G is the substitution that was made for the generator.
+	G MaliciousG
+	// Secret is the shared secret the two parties ended up with.
+	Secret *big.Int
+	// Plaintexts are the decrypted messages relayed between the two parties,
+	// in the order they were sent.
+	Plaintexts [][]byte
+}
+
+// MaliciousGMITM implements the malicious "g" attack on the negotiated group
+// echo protocol. It substitutes G into the group proposed by the client, which
+// the server then acknowledges and both parties go on to use. Every possible
+// shared secret under the malicious generator is known in advance, so once the
+// protocol finishes, the secret actually used is the one whose key decrypts
+// every relayed message to a validly padded plaintext.
+type MaliciousGMITM struct {
+	G      MaliciousG
+	Result MaliciousGResult
+}
+
+var errNoCandidateSecret = errors.New("no candidate secret decrypts every message")
+
+// Intercept acts as the MITM (M).
+func (m *MaliciousGMITM) Intercept(ctx context.Context, alice, bob *protocol.Conn) error {
+	defer alice.Close()
+	defer bob.Close()
+
+	proposal, err := protocol.Receive[DHGroupProposal](ctx, alice)
+	if err != nil {
+		return fmt.Errorf("receiving group proposal: %w", err)
+	}
+	proposal.G = m.G.generator(proposal.P)
+	if err := bob.Send(ctx, proposal); err != nil {
+		return fmt.Errorf("sending group proposal: %w", err)
+	}
+
+	// Relay the group ack and both public keys untouched.
+	if err := relay(ctx, bob, alice); err != nil {
+		return fmt.Errorf("relaying group ack: %w", err)
+	}
+	if err := relay(ctx, alice, bob); err != nil {
+		return fmt.Errorf("relaying alice's public key: %w", err)
+	}
+	if err := relay(ctx, bob, alice); err != nil {
+		return fmt.Errorf("relaying bob's public key: %w", err)
+	}
+
+	var captured []EncryptedMessage
+	err = relayEncrypted(ctx, alice, bob, func(msg EncryptedMessage) error {
+		captured = append(captured, msg)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	m.Result, err = recoverMaliciousGPlaintexts(m.G, proposal.P, captured)
+	return err
+}
+
+// relay receives a single message from one connection and sends it on the
+// other.
+func relay(ctx context.Context, from, to *protocol.Conn) error {
+	msg, err := from.Recv(ctx)
+	if err != nil {
+		return err
+	}
+	return to.Send(ctx, msg)
+}
+
+func recoverMaliciousGPlaintexts(g MaliciousG, p *big.Int, captured []EncryptedMessage) (MaliciousGResult, error) {
+	for _, secret := range g.candidateSecrets(p) {
+		key := dh.DeriveKey(secret)
+		plaintexts := make([][]byte, 0, len(captured))
+		for _, msg := range captured {
+			plaintext, err := decryptMessage(key, msg)
+			if err != nil {
+				break
+			}
+			plaintexts = append(plaintexts, plaintext)
+		}
+		if len(plaintexts) == len(captured) {
+			return MaliciousGResult{G: g, Secret: secret, Plaintexts: plaintexts}, nil
+		}
+	}
+	return MaliciousGResult{G: g}, errNoCandidateSecret
+}
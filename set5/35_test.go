@@ -0,0 +1,78 @@
+package set5
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/protocol"
+)
+
+func TestChallenge35_EchoProtocol(t *testing.T) {
+	client := NewNegotiatedDHEchoClient(dh.NISTGroup(), challenge34Messages)
+
+	if err := protocol.Run(context.Background(), client, NegotiatedDHEchoServer, nil); err != nil {
+		t.Fatalf("running echo protocol: %v", err)
+	}
+}
+
+func TestChallenge35_MaliciousGMITM(t *testing.T) {
+	p := dh.NISTGroup().P
+	pMinusOne := new(big.Int).Sub(p, big.NewInt(1))
+
+	tt := []struct {
+		g             MaliciousG
+		wantSecretsIn []*big.Int
+	}{
+		{GEqualsOne, []*big.Int{big.NewInt(1)}},
+		{GEqualsP, []*big.Int{big.NewInt(0)}},
+		{GEqualsPMinusOne, []*big.Int{big.NewInt(1), pMinusOne}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.g.String(), func(t *testing.T) {
+			// Run a few times so g = p - 1 is likely to see both secrets.
+			for i := 0; i < 8; i++ {
+				client := NewNegotiatedDHEchoClient(dh.NISTGroup(), challenge34Messages)
+				mitm := &MaliciousGMITM{G: tc.g}
+
+				err := protocol.Run(context.Background(), client, NegotiatedDHEchoServer, mitm.Intercept)
+				if err != nil {
+					t.Fatalf("running echo protocol: %v", err)
+				}
+
+				got := mitm.Result
+				if tc.g != got.G {
+					t.Fatalf("want substitution: %v, got: %v", tc.g, got.G)
+				}
+				if !containsInt(tc.wantSecretsIn, got.Secret) {
+					t.Fatalf("want secret in: %v, got: %v", tc.wantSecretsIn, got.Secret)
+				}
+
+				var want [][]byte
+				for _, msg := range challenge34Messages {
+					want = append(want, msg, msg)
+				}
+				if len(want) != len(got.Plaintexts) {
+					t.Fatalf("want %d plaintexts, got %d", len(want), len(got.Plaintexts))
+				}
+				for i := range want {
+					if !bytes.Equal(want[i], got.Plaintexts[i]) {
+						t.Errorf("[%d]: want: '%s', got: '%s'", i, want[i], got.Plaintexts[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func containsInt(s []*big.Int, x *big.Int) bool {
+	for _, v := range s {
+		if v.Cmp(x) == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package set5
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/saclark/cryptopals/srp"
+)
+
+func TestChallenge36(t *testing.T) {
+	server := srp.NewServer(srp.NISTParams(), rand.Reader)
+	if err := server.Register("alice@example.com", []byte("correct horse battery staple")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	ts := httptest.NewServer(srp.NewHandler(server))
+	defer ts.Close()
+
+	auth := &srp.HTTPAuthenticator{Client: ts.Client(), BaseURL: ts.URL}
+
+	err := LoginWithSRP(context.Background(), auth, "alice@example.com", []byte("correct horse battery staple"))
+	if err != nil {
+		t.Fatalf("want successful login, got: %v", err)
+	}
+
+	err = LoginWithSRP(context.Background(), auth, "alice@example.com", []byte("incorrect horse battery staple"))
+	if !errors.Is(err, srp.ErrAuthenticationFailed) {
+		t.Fatalf("want: %v, got: %v", srp.ErrAuthenticationFailed, err)
+	}
+}
@@ -0,0 +1,56 @@
+// # Break SRP with a zero key
+//
+// Get your SRP working in an actual client-server setting. "Log in" with a
+// valid password using the protocol.
+//
+// Now log in without your password by having the client send 0 as its "A"
+// value. What does this to the "S" value that both sides compute?
+//
+// Now log in without your password by having the client send N, N*2, &c.
+//
+// > # Cryptanalytic MVP award
+// > Trevor Perrin and Nate Lawson taught us this attack 7 years ago. It is
+// > excellent. Attacks on DH are tricky to "operationalize". But this attack
+// > uses the same concepts, and results in auth bypass. Almost every
+// > implementation of SRP we've ever seen has this flaw; if you see a new one,
+// > go look for this bug.
+
+package set5
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/srp"
+)
+
+// BypassSRPWithZeroKey logs in to auth as email without knowing the password
+// by sending A = multiple * N. The server computes its premaster secret as:
+//
+//	S = (A * v^u)^b mod N = (multiple * N * v^u)^b mod N = 0
+//
+// so the session key is just SHA256(0), and we can compute a proof the server
+// will accept without ever touching the password. A server that rejects A ≡ 0
+// (mod N) is not vulnerable.
+func BypassSRPWithZeroKey(
+	ctx context.Context,
+	auth srp.Authenticator,
+	params *srp.Params,
+	email string,
+	multiple int64,
+) error {
+	A := new(big.Int).Mul(params.N, big.NewInt(multiple))
+
+	salt, _, err := auth.Start(ctx, email, A)
+	if err != nil {
+		return fmt.Errorf("starting login with A = %d * N: %w", multiple, err)
+	}
+
+	proof := srp.Proof(srp.SessionKey(big.NewInt(0)), salt)
+	if err := auth.Verify(ctx, email, proof); err != nil {
+		return fmt.Errorf("verifying login with A = %d * N: %w", multiple, err)
+	}
+
+	return nil
+}
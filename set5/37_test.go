@@ -0,0 +1,131 @@
+package set5
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/saclark/cryptopals/srp"
+)
+
+func TestChallenge37(t *testing.T) {
+	params := srp.NISTParams()
+	email := "alice@example.com"
+
+	victim := NewZeroKeyVulnerableSRPServer(params)
+	if err := victim.Register(email, []byte("not a dictionary word")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	ts := httptest.NewServer(srp.NewHandler(victim))
+	defer ts.Close()
+
+	tt := []struct {
+		name string
+		auth srp.Authenticator
+	}{
+		{"in-process", victim},
+		{"http", &srp.HTTPAuthenticator{Client: ts.Client(), BaseURL: ts.URL}},
+	}
+
+	for _, tc := range tt {
+		for multiple := int64(0); multiple <= 3; multiple++ {
+			t.Run(fmt.Sprintf("%s,A=%d*N", tc.name, multiple), func(t *testing.T) {
+				err := BypassSRPWithZeroKey(context.Background(), tc.auth, params, email, multiple)
+				if err != nil {
+					t.Fatalf("want successful login, got: %v", err)
+				}
+			})
+		}
+	}
+}
+
+func TestChallenge37_FailsAgainstServerThatValidatesA(t *testing.T) {
+	params := srp.NISTParams()
+	server := srp.NewServer(params, rand.Reader)
+	if err := server.Register("alice@example.com", []byte("not a dictionary word")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	err := BypassSRPWithZeroKey(context.Background(), server, params, "alice@example.com", 0)
+	if !errors.Is(err, srp.ErrInvalidPublicKey) {
+		t.Fatalf("want: %v, got: %v", srp.ErrInvalidPublicKey, err)
+	}
+}
+
+// ZeroKeyVulnerableSRPServer implements a minimal SRP server that, unlike
+// srp.Server, neglects to check that A is not congruent to 0 mod N.
+type ZeroKeyVulnerableSRPServer struct {
+	params *srp.Params
+
+	mu       sync.Mutex
+	salts    map[string][]byte
+	vs       map[string]*big.Int
+	sessions map[string][]byte
+}
+
+func NewZeroKeyVulnerableSRPServer(params *srp.Params) *ZeroKeyVulnerableSRPServer {
+	return &ZeroKeyVulnerableSRPServer{
+		params:   params,
+		salts:    map[string][]byte{},
+		vs:       map[string]*big.Int{},
+		sessions: map[string][]byte{},
+	}
+}
+
+func (s *ZeroKeyVulnerableSRPServer) Register(email string, password []byte) error {
+	salt, v, err := srp.NewVerifier(s.params, password, rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.salts[email] = salt
+	s.vs[email] = v
+	return nil
+}
+
+func (s *ZeroKeyVulnerableSRPServer) Start(ctx context.Context, email string, A *big.Int) (salt []byte, B *big.Int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	salt, v := s.salts[email], s.vs[email]
+	if v == nil {
+		return nil, nil, srp.ErrUnknownUser
+	}
+
+	b, err := rand.Int(rand.Reader, s.params.N)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	B = new(big.Int).Exp(s.params.G, b, s.params.N)
+	B.Add(B, new(big.Int).Mul(s.params.Multiplier(), v))
+	B.Mod(B, s.params.N)
+
+	u := s.params.Scramble(A, B)
+	S := new(big.Int).Exp(v, u, s.params.N)
+	S.Mul(S, A)
+	S.Exp(S, b, s.params.N)
+
+	s.sessions[email] = srp.Proof(srp.SessionKey(S), salt)
+	return salt, B, nil
+}
+
+func (s *ZeroKeyVulnerableSRPServer) Verify(ctx context.Context, email string, proof []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	want, ok := s.sessions[email]
+	delete(s.sessions, email)
+	if !ok || subtle.ConstantTimeCompare(want, proof) != 1 {
+		return srp.ErrAuthenticationFailed
+	}
+	return nil
+}
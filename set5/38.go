@@ -0,0 +1,248 @@
+// # Offline dictionary attack on simplified SRP
+//
+//	S
+//	    x = SHA256(salt|password)
+//	    v = g**x % n
+//	C->S
+//	    I, A = g**a % n
+//	S->C
+//	    salt, B = g**b % n, u = 128 bit random number
+//	C
+//	    x = SHA256(salt|password)
+//	    S = B**(a + ux) % n
+//	    K = SHA256(S)
+//	S
+//	    S = (A * v ** u)**b % n
+//	    K = SHA256(S)
+//	C->S
+//	    Send HMAC-SHA256(K, salt)
+//	S->C
+//	    Send "OK" if HMAC-SHA256(K, salt) validates
+//
+// Note that in this protocol, the server's "B" parameter doesn't depend on the
+// password (it's just a Diffie Hellman public key).
+//
+// Make sure the protocol works given a valid password.
+//
+// Now, run the protocol as a MITM attacker: pose as the server and use
+// arbitrary values for b, B, u, and salt.
+//
+// Crack the password from A's HMAC-SHA256(K, salt).
+
+package set5
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/saclark/cryptopals/srp"
+)
+
+// SimplifiedSRPAuthenticator is the server side of a simplified SRP login.
+type SimplifiedSRPAuthenticator interface {
+	// Start begins a login for email with the client's public ephemeral value,
+	// A. It returns the user's salt, the server's public ephemeral value, B,
+	// and the scrambling parameter, u.
+	Start(ctx context.Context, email string, A *big.Int) (salt []byte, B, u *big.Int, err error)
+	// Verify completes the login for email, returning nil if proof shows the
+	// client derived the same session key as the server.
+	Verify(ctx context.Context, email string, proof []byte) error
+}
+
+// LoginWithSimplifiedSRP logs in to auth with the given email and password
+// using simplified SRP.
+func LoginWithSimplifiedSRP(
+	ctx context.Context,
+	auth SimplifiedSRPAuthenticator,
+	params *srp.Params,
+	email string,
+	password []byte,
+) error {
+	a, err := rand.Int(rand.Reader, params.N)
+	if err != nil {
+		return fmt.Errorf("generating private ephemeral value: %w", err)
+	}
+	A := new(big.Int).Exp(params.G, a, params.N)
+
+	salt, B, u, err := auth.Start(ctx, email, A)
+	if err != nil {
+		return fmt.Errorf("starting login: %w", err)
+	}
+
+	// S = B^(a + u * x) mod N
+	x := srp.PrivateKey(salt, password)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, a)
+	S := new(big.Int).Exp(B, exp, params.N)
+
+	if err := auth.Verify(ctx, email, srp.Proof(srp.SessionKey(S), salt)); err != nil {
+		return fmt.Errorf("verifying login: %w", err)
+	}
+	return nil
+}
+
+// SimplifiedSRPCapture is everything a MITM posing as a simplified SRP server
+// needs to crack the client's password offline.
+type SimplifiedSRPCapture struct {
+	Email string
+	A     *big.Int
+	Salt  []byte
+	B     *big.Int
+	U     *big.Int
+	// PrivateB is the b the MITM chose, such that B = g^b mod N.
+	PrivateB *big.Int
+	Proof    []byte
+}
+
+// SimplifiedSRPMITM poses as a simplified SRP server, choosing b = 1, B = g,
+// u = 1 and an empty salt, and accepts any login so the client is none the
+// wiser. Captures holds what was captured from each login.
+type SimplifiedSRPMITM struct {
+	Params *srp.Params
+
+	mu       sync.Mutex
+	Captures []SimplifiedSRPCapture
+}
+
+// Start implements SimplifiedSRPAuthenticator.
+func (m *SimplifiedSRPMITM) Start(ctx context.Context, email string, A *big.Int) (salt []byte, B, u *big.Int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := SimplifiedSRPCapture{
+		Email:    email,
+		A:        A,
+		Salt:     []byte{},
+		B:        m.Params.G,
+		U:        big.NewInt(1),
+		PrivateB: big.NewInt(1),
+	}
+	m.Captures = append(m.Captures, c)
+	return c.Salt, c.B, c.U, nil
+}
+
+// Verify implements SimplifiedSRPAuthenticator.
+func (m *SimplifiedSRPMITM) Verify(ctx context.Context, email string, proof []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.Captures) - 1; i >= 0; i-- {
+		if m.Captures[i].Email == email && m.Captures[i].Proof == nil {
+			m.Captures[i].Proof = proof
+			return nil
+		}
+	}
+	return srp.ErrAuthenticationFailed
+}
+
+// ErrPasswordNotFound is returned when no word in a wordlist matches a
+// captured simplified SRP login.
+var ErrPasswordNotFound = errors.New("password not found in wordlist")
+
+// CrackSimplifiedSRPPassword recovers the password used in a captured
+// simplified SRP login by trying every line of wordlist as a candidate. For
+// each candidate password we compute x, then the server-side premaster secret
+// using our chosen b and u:
+//
+//	S = (A * g^(u * x))^b mod N
+//
+// and check whether HMAC-SHA256(SHA256(S), salt) matches the captured proof.
+// Candidates are checked concurrently by the given number of workers.
+//
+// An error is returned if ctx is done, if reading the wordlist fails or if no
+// candidate matches. When not nil, logf is used to log the attack's progress.
+//
+// It panics if workers is less than 1.
+func CrackSimplifiedSRPPassword(
+	ctx context.Context,
+	params *srp.Params,
+	capture SimplifiedSRPCapture,
+	wordlist io.Reader,
+	workers int,
+	logf func(format string, a ...any),
+) ([]byte, error) {
+	if workers < 1 {
+		panic("workers not > 0")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		in      = make(chan []byte)
+		found   = make(chan []byte, 1)
+		scanErr error
+		wg      sync.WaitGroup
+	)
+
+	// Feed candidates to the workers.
+	go func() {
+		defer close(in)
+		scanner := bufio.NewScanner(wordlist)
+		for n := 1; scanner.Scan(); n++ {
+			select {
+			case in <- bytes.Clone(scanner.Bytes()):
+			case <-ctx.Done():
+				return
+			}
+			if logf != nil && n%crackProgressInterval == 0 {
+				logf("tried %d candidate passwords\n", n)
+			}
+		}
+		scanErr = scanner.Err()
+	}()
+
+	// Run workers to check candidates.
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for password := range in {
+				if simplifiedSRPProofMatches(params, capture, password) {
+					select {
+					case found <- password:
+						cancel()
+					default:
+					}
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	select {
+	case password := <-found:
+		if logf != nil {
+			logf("found password: %s\n", password)
+		}
+		return password, nil
+	default:
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if scanErr != nil {
+		return nil, fmt.Errorf("reading wordlist: %w", scanErr)
+	}
+	return nil, ErrPasswordNotFound
+}
+
+// How many candidates to try between progress reports.
+const crackProgressInterval = 1 << 10
+
+func simplifiedSRPProofMatches(params *srp.Params, capture SimplifiedSRPCapture, password []byte) bool {
+	x := srp.PrivateKey(capture.Salt, password)
+	S := new(big.Int).Mul(capture.U, x)
+	S.Exp(params.G, S, params.N)
+	S.Mul(S, capture.A)
+	S.Exp(S, capture.PrivateB, params.N)
+	return bytes.Equal(capture.Proof, srp.Proof(srp.SessionKey(S), capture.Salt))
+}
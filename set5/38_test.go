@@ -0,0 +1,197 @@
+package set5
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/srp"
+)
+
+func TestChallenge38_ProtocolWorks(t *testing.T) {
+	params := srp.NISTParams()
+	server := NewSimplifiedSRPServer(params)
+	if err := server.Register("alice@example.com", []byte("sunshine")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	err := LoginWithSimplifiedSRP(context.Background(), server, params, "alice@example.com", []byte("sunshine"))
+	if err != nil {
+		t.Fatalf("want successful login, got: %v", err)
+	}
+
+	err = LoginWithSimplifiedSRP(context.Background(), server, params, "alice@example.com", []byte("moonshine"))
+	if !errors.Is(err, srp.ErrAuthenticationFailed) {
+		t.Fatalf("want: %v, got: %v", srp.ErrAuthenticationFailed, err)
+	}
+}
+
+func TestChallenge38_OfflineDictionaryAttack(t *testing.T) {
+	params := srp.NISTParams()
+	words := strings.Fields(challenge38Words)
+	want := []byte(words[testutil.MustRandomInt(len(words))])
+
+	mitm := &SimplifiedSRPMITM{Params: params}
+	err := LoginWithSimplifiedSRP(context.Background(), mitm, params, "alice@example.com", want)
+	if err != nil {
+		t.Fatalf("logging in: %v", err)
+	}
+	if len(mitm.Captures) != 1 {
+		t.Fatalf("want 1 capture, got %d", len(mitm.Captures))
+	}
+
+	got, err := CrackSimplifiedSRPPassword(
+		context.Background(),
+		params,
+		mitm.Captures[0],
+		newChallenge38WordlistReader(),
+		runtime.GOMAXPROCS(0),
+		t.Logf,
+	)
+	if err != nil {
+		t.Fatalf("want password '%s', got error: %v", want, err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+}
+
+func TestChallenge38_PasswordNotInWordlist(t *testing.T) {
+	params := srp.NISTParams()
+	mitm := &SimplifiedSRPMITM{Params: params}
+	err := LoginWithSimplifiedSRP(context.Background(), mitm, params, "alice@example.com", []byte("Tr0ub4dor&3"))
+	if err != nil {
+		t.Fatalf("logging in: %v", err)
+	}
+
+	_, err = CrackSimplifiedSRPPassword(
+		context.Background(),
+		params,
+		mitm.Captures[0],
+		newChallenge38WordlistReader(),
+		4,
+		nil,
+	)
+	if !errors.Is(err, ErrPasswordNotFound) {
+		t.Fatalf("want: %v, got: %v", ErrPasswordNotFound, err)
+	}
+}
+
+func TestChallenge38_Cancellation(t *testing.T) {
+	params := srp.NISTParams()
+	mitm := &SimplifiedSRPMITM{Params: params}
+	err := LoginWithSimplifiedSRP(context.Background(), mitm, params, "alice@example.com", []byte("Tr0ub4dor&3"))
+	if err != nil {
+		t.Fatalf("logging in: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = CrackSimplifiedSRPPassword(
+		ctx,
+		params,
+		mitm.Captures[0],
+		newChallenge38WordlistReader(),
+		4,
+		nil,
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("want: %v, got: %v", context.Canceled, err)
+	}
+}
+
+// SimplifiedSRPServer implements a legitimate simplified SRP server.
+type SimplifiedSRPServer struct {
+	params *srp.Params
+
+	mu       sync.Mutex
+	salts    map[string][]byte
+	vs       map[string]*big.Int
+	sessions map[string][]byte
+}
+
+func NewSimplifiedSRPServer(params *srp.Params) *SimplifiedSRPServer {
+	return &SimplifiedSRPServer{
+		params:   params,
+		salts:    map[string][]byte{},
+		vs:       map[string]*big.Int{},
+		sessions: map[string][]byte{},
+	}
+}
+
+func (s *SimplifiedSRPServer) Register(email string, password []byte) error {
+	salt, v, err := srp.NewVerifier(s.params, password, rand.Reader)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.salts[email] = salt
+	s.vs[email] = v
+	return nil
+}
+
+func (s *SimplifiedSRPServer) Start(ctx context.Context, email string, A *big.Int) (salt []byte, B, u *big.Int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	salt, v := s.salts[email], s.vs[email]
+	if v == nil {
+		return nil, nil, nil, srp.ErrUnknownUser
+	}
+
+	b := testutil.Must(rand.Int(rand.Reader, s.params.N))
+	B = new(big.Int).Exp(s.params.G, b, s.params.N)
+	u = new(big.Int).SetBytes(testutil.MustRandomBytes(16))
+
+	// S = (A * v^u)^b mod N
+	S := new(big.Int).Exp(v, u, s.params.N)
+	S.Mul(S, A)
+	S.Exp(S, b, s.params.N)
+
+	s.sessions[email] = srp.Proof(srp.SessionKey(S), salt)
+	return salt, B, u, nil
+}
+
+func (s *SimplifiedSRPServer) Verify(ctx context.Context, email string, proof []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	want, ok := s.sessions[email]
+	delete(s.sessions, email)
+	if !ok || subtle.ConstantTimeCompare(want, proof) != 1 {
+		return srp.ErrAuthenticationFailed
+	}
+	return nil
+}
+
+// newChallenge38WordlistReader returns a reader over challenge38Words, one per
+// line.
+func newChallenge38WordlistReader() io.Reader {
+	return strings.NewReader(strings.Join(strings.Fields(challenge38Words), "\n"))
+}
+
+const challenge38Words = `
+123456 password 12345678 qwerty 123456789 12345 1234 111111 1234567 dragon
+123123 baseball abc123 football monkey letmein 696969 shadow master 666666
+qwertyuiop 123321 mustang 1234567890 michael 654321 superman 1qaz2wsx 7777777
+121212 000000 qazwsx 123qwe killer trustno1 jordan jennifer zxcvbnm asdfgh
+hunter buster soccer harley batman andrew tigger sunshine iloveyou 2000
+charlie robert thomas hockey ranger daniel starwars klaster 112233 george
+computer michelle jessica pepper 1111 zxcvbn 555555 11111111 131313 freedom
+777777 pass maggie 159753 aaaaaa ginger princess joshua cheese amanda summer
+love ashley nicole chelsea biteme matthew access yankees 987654321 dallas
+austin thunder taylor matrix mobilemail mom monitor monitoring montana moon
+moscow orange purple silver golden banana cookie yellow submarine bacon
+`
@@ -0,0 +1,64 @@
+// # Implement RSA
+//
+// There are two annoying things about implementing RSA. Both of them involve
+// key generation; the actual encryption/decryption in RSA is trivial.
+//
+// First, you need to generate random primes. You can't just agree on a prime
+// ahead of time, like you do in DH. You can write this algorithm yourself, but
+// I just cheat and use OpenSSL's BN library to do the work.
+//
+// The second is that you need an "invmod" operation (the multiplicative
+// inverse), which is not an operation that is wired into your language. The
+// algorithm is just a couple lines, but I always lose an hour getting it to
+// work.
+//
+// I recommend you not bother with primegen, but do take the time to get your
+// own EGCD and invmod algorithm working.
+//
+// Now:
+//
+//   - Generate 2 random primes. We'll use small numbers to start, so you can
+//     just pick them out of a prime table. Potentially, to get a prime, you
+//     could keep generating random numbers until you find one that is prime.
+//   - Let n be p * q. Your RSA math is modulo n.
+//   - Let et be (p-1)*(q-1) (the "totient"). You need this value only for
+//     keygen.
+//   - Let e be 3.
+//   - Compute d = invmod(e, et). invmod(17, 3120) is 2753.
+//   - Your public key is [e, n]. Your private key is [d, n].
+//   - To encrypt: c = m**e%n. To decrypt: m = c**d%n
+//   - Test this out with a number, like "42".
+//   - Repeat with bignum primes (keep e=3).
+//
+// Finally, to encrypt a string, do something cheesy, like convert the string to
+// hex and put "0x" on the front of it to turn it into a number. The math cares
+// not how stupidly you feed it strings.
+
+package set5
+
+import (
+	"fmt"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// RSAEncryptString encrypts s by interpreting its bytes as a big-endian
+// integer, which is exactly as cheesy as hex encoding it and putting "0x" on
+// the front.
+func RSAEncryptString(pub *rsa.PublicKey, s string) ([]byte, error) {
+	c, err := rsa.Encrypt(pub, rsa.BytesToInt([]byte(s)))
+	if err != nil {
+		return nil, fmt.Errorf("RSA encrypting: %w", err)
+	}
+	return rsa.IntToBytes(c, pub.Size()), nil
+}
+
+// RSADecryptString reverses RSAEncryptString. Leading zero bytes of the
+// original string are not recovered.
+func RSADecryptString(priv *rsa.PrivateKey, ciphertext []byte) (string, error) {
+	m, err := rsa.Decrypt(priv, rsa.BytesToInt(ciphertext))
+	if err != nil {
+		return "", fmt.Errorf("RSA decrypting: %w", err)
+	}
+	return string(m.Bytes()), nil
+}
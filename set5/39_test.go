@@ -0,0 +1,58 @@
+package set5
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestChallenge39_SmallPrimes(t *testing.T) {
+	priv, err := rsa.NewPrivateKey(big.NewInt(61), big.NewInt(53), 17)
+	if err != nil {
+		t.Fatalf("creating key: %v", err)
+	}
+	if want := big.NewInt(2753); want.Cmp(priv.D) != 0 {
+		t.Fatalf("want d: %d, got: %d", want, priv.D)
+	}
+
+	m := big.NewInt(42)
+	c, err := rsa.Encrypt(&priv.PublicKey, m)
+	if err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	got, err := rsa.Decrypt(priv, c)
+	if err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+	if m.Cmp(got) != 0 {
+		t.Fatalf("want: %d, got: %d", m, got)
+	}
+}
+
+func TestChallenge39_String(t *testing.T) {
+	for _, e := range []int{3, 65537} {
+		t.Run(fmt.Sprintf("e=%d", e), func(t *testing.T) {
+			priv, err := rsa.GenerateKey(rand.Reader, 1024, e)
+			if err != nil {
+				t.Fatalf("generating key: %v", err)
+			}
+
+			want := "Ice, Ice, baby"
+			ciphertext, err := RSAEncryptString(&priv.PublicKey, want)
+			if err != nil {
+				t.Fatalf("encrypting: %v", err)
+			}
+			got, err := RSADecryptString(priv, ciphertext)
+			if err != nil {
+				t.Fatalf("decrypting: %v", err)
+			}
+
+			if want != got {
+				t.Fatalf("want: '%s', got: '%s'", want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+// # Implement an E=3 RSA Broadcast attack
+//
+// Assume you're a Javascript programmer. That is, you're using a naive
+// handrolled RSA to encrypt without padding.
+//
+// Assume you can be coerced into encrypting the same plaintext three times,
+// under three different public keys. You can; it's happened.
+//
+// Then an attacker can trivially decrypt your message, by:
+//
+//  1. Capturing any 3 of the ciphertexts and their corresponding pubkeys
+//  2. Using the CRT to solve for the number represented by the three
+//     ciphertexts (which are residues mod their respective pubkeys)
+//  3. Taking the cube root of the resulting number
+//
+// The CRT says you can take any number and represent it as the combination of a
+// series of residues mod a series of moduli. In the three-residue case, you
+// have:
+//
+//	result =
+//	  (c_0 * m_s_0 * invmod(m_s_0, n_0)) +
+//	  (c_1 * m_s_1 * invmod(m_s_1, n_1)) +
+//	  (c_2 * m_s_2 * invmod(m_s_2, n_2)) mod N_012
+//
+// where:
+//
+//	c_0, c_1, c_2 are the three respective residues mod
+//	n_0, n_1, n_2
+//
+//	m_s_n (for n in 0, 1, 2) are the product of the moduli
+//	EXCEPT n_n --- ie, m_s_1 is n_0 * n_2
+//
+//	N_012 is the product of all three moduli
+//
+// To decrypt RSA using a simple cube root, leave off the final modulus
+// operation; just take the raw accumulated result and cube-root it.
+
+package set5
+
+import (
+	"fmt"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// RecoverE3BroadcastString recovers a string encrypted with RSAEncryptString
+// under three or more different e=3 public keys.
+func RecoverE3BroadcastString(pubs []*rsa.PublicKey, ciphertexts [][]byte) (string, error) {
+	if len(pubs) != len(ciphertexts) {
+		return "", fmt.Errorf("got %d public keys but %d ciphertexts", len(pubs), len(ciphertexts))
+	}
+
+	cts := make([]attack.RSACiphertext, len(pubs))
+	for i, pub := range pubs {
+		if pub.E != 3 {
+			return "", fmt.Errorf("public key %d: want e = 3, got %d", i, pub.E)
+		}
+		cts[i] = attack.RSACiphertext{N: pub.N, C: rsa.BytesToInt(ciphertexts[i])}
+	}
+
+	m, err := attack.RecoverRSABroadcastPlaintext(3, cts)
+	if err != nil {
+		return "", fmt.Errorf("recovering broadcast plaintext: %w", err)
+	}
+
+	return string(m.Bytes()), nil
+}
@@ -0,0 +1,36 @@
+package set5
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestChallenge40(t *testing.T) {
+	want := "Assume you can be coerced into encrypting the same plaintext three times"
+
+	var pubs []*rsa.PublicKey
+	var ciphertexts [][]byte
+	for i := 0; i < 3; i++ {
+		priv, err := rsa.GenerateKey(rand.Reader, 1024, 3)
+		if err != nil {
+			t.Fatalf("generating key: %v", err)
+		}
+		ciphertext, err := RSAEncryptString(&priv.PublicKey, want)
+		if err != nil {
+			t.Fatalf("encrypting: %v", err)
+		}
+		pubs = append(pubs, &priv.PublicKey)
+		ciphertexts = append(ciphertexts, ciphertext)
+	}
+
+	got, err := RecoverE3BroadcastString(pubs, ciphertexts)
+	if err != nil {
+		t.Fatalf("recovering plaintext: %v", err)
+	}
+
+	if want != got {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+}
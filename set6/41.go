@@ -0,0 +1,144 @@
+// # Implement unpadded message recovery oracle
+//
+// Nate Lawson says we should stop calling it "RSA padding" and start calling it
+// "RSA armoring". Here's why.
+//
+// Imagine a web application, again with the Javascript encryption, taking
+// RSA-encrypted messages which (again: Javascript) aren't padded before
+// encryption at all.
+//
+// You can submit an arbitrary RSA blob to a server that will decrypt it and
+// return the plaintext. But you can't submit the same message twice: let's say
+// the server keeps hashes of previous messages for some liveness interval, and
+// that the message has an embedded timestamp:
+//
+//	{
+//	  time: 1356304276,
+//	  social: '555-55-5555',
+//	}
+//
+// You'd like to capture other people's messages and use the server to decrypt
+// them. But when you try, the server takes the hash of the ciphertext and uses
+// it to reject the request. Any bit you flip in the ciphertext irrevocably
+// scrambles the decryption.
+//
+// This turns out to be trivially breakable:
+//
+//   - Capture the ciphertext C
+//   - Let N and E be the public modulus and exponent respectively
+//   - Let S be a random number > 1 mod N. Doesn't matter what.
+//   - Now:
+//
+//     C' = ((S**E mod N) C) mod N
+//
+//   - Submit C', which appears totally different from C, to the server,
+//     recovering P', which appears totally different from P
+//   - Now:
+//
+//     P = P' / S mod N
+//
+// Oops!
+//
+// Implement that attack.
+//
+// Careful about division in cyclic groups.
+//
+// Remember: you don't simply divide mod N; you multiply by the multiplicative
+// inverse mod N. So you'll need a modinv() function.
+
+package set6
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// NewRSADecryptFunc returns a function that submits a ciphertext to the
+// vulnerable decryption server and returns the plaintext it responds with.
+func NewRSADecryptFunc(
+	client *http.Client,
+	baseURL string,
+) func(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return func(ctx context.Context, ciphertext []byte) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/decrypt", bytes.NewReader(ciphertext))
+		if err != nil {
+			return nil, fmt.Errorf("building request: %w", err)
+		}
+		req.Header.Add("Content-Type", "application/octet-stream")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("sending request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != 200 {
+			return nil, fmt.Errorf("unexpected response status code: %d", resp.StatusCode)
+		}
+
+		plaintext, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading response body: %w", err)
+		}
+
+		return plaintext, nil
+	}
+}
+
+// RecoverUnpaddedRSAPlaintext uses a decryption oracle that will not decrypt
+// the same ciphertext twice to decrypt a ciphertext that it has already seen.
+//
+// The ciphertext C is blinded by multiplying it by S^E for a random S. Since
+// (S^E * C)^D = S * P (mod N), the oracle's response to the blinded ciphertext
+// need only be multiplied by the inverse of S to recover P.
+func RecoverUnpaddedRSAPlaintext(
+	ctx context.Context,
+	pub *rsa.PublicKey,
+	ciphertext []byte,
+	decrypt func(ctx context.Context, ciphertext []byte) ([]byte, error),
+) ([]byte, error) {
+	s, sInv, err := randomRSABlindingFactor(pub.N)
+	if err != nil {
+		return nil, fmt.Errorf("generating blinding factor: %w", err)
+	}
+
+	c := rsa.BytesToInt(ciphertext)
+	blinded, err := rsa.Encrypt(pub, s)
+	if err != nil {
+		return nil, fmt.Errorf("RSA encrypting blinding factor: %w", err)
+	}
+	blinded.Mul(blinded, c).Mod(blinded, pub.N)
+
+	blindedPlaintext, err := decrypt(ctx, rsa.IntToBytes(blinded, pub.Size()))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting blinded ciphertext: %w", err)
+	}
+
+	p := rsa.BytesToInt(blindedPlaintext)
+	p.Mul(p, sInv).Mod(p, pub.N)
+
+	return p.Bytes(), nil
+}
+
+// randomRSABlindingFactor returns a random S in [2, N) that is invertible mod
+// N, along with its inverse.
+func randomRSABlindingFactor(n *big.Int) (s, sInv *big.Int, err error) {
+	max := new(big.Int).Sub(n, big.NewInt(2))
+	for {
+		s, err = rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.Add(s, big.NewInt(2))
+		if sInv, err = rsa.InvMod(s, n); err == nil {
+			return s, sInv, nil
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package set6
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestChallenge41(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024, 65537)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	ts := httptest.NewServer(handleRSADecryption(priv))
+	defer ts.Close()
+	decrypt := NewRSADecryptFunc(ts.Client(), ts.URL)
+
+	want := fmt.Sprintf("{\n  time: %d,\n  social: '555-55-5555',\n}", time.Now().Unix())
+	c, err := rsa.Encrypt(&priv.PublicKey, rsa.BytesToInt([]byte(want)))
+	if err != nil {
+		t.Fatalf("encrypting: %v", err)
+	}
+	ciphertext := rsa.IntToBytes(c, priv.Size())
+
+	// The legitimate recipient decrypts the message first.
+	ctx := context.Background()
+	if _, err := decrypt(ctx, ciphertext); err != nil {
+		t.Fatalf("decrypting original ciphertext: %v", err)
+	}
+	if _, err := decrypt(ctx, ciphertext); err == nil {
+		t.Fatal("want server to refuse to decrypt the same ciphertext twice, got nil error")
+	}
+
+	got, err := RecoverUnpaddedRSAPlaintext(ctx, &priv.PublicKey, ciphertext, decrypt)
+	if err != nil {
+		t.Fatalf("recovering plaintext: %v", err)
+	}
+
+	if want != string(got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+}
+
+// handleRSADecryption implements a server endpoint that decrypts unpadded RSA
+// ciphertexts, refusing to decrypt any ciphertext it has seen before.
+func handleRSADecryption(priv *rsa.PrivateKey) http.HandlerFunc {
+	var mu sync.Mutex
+	seen := make(map[[sha256.Size]byte]bool)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/decrypt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if r.Header.Get("Content-Type") != "application/octet-stream" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		ciphertext, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		hash := sha256.Sum256(ciphertext)
+		mu.Lock()
+		replayed := seen[hash]
+		seen[hash] = true
+		mu.Unlock()
+		if replayed {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		m, err := rsa.Decrypt(priv, rsa.BytesToInt(ciphertext))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/octet-stream")
+		w.Write(m.Bytes())
+	}
+}
@@ -0,0 +1,84 @@
+// # Bleichenbacher's e=3 RSA Attack
+//
+// Crypto-tourism informational placard.
+//
+// This attack broke Firefox's TLS certificate validation several years ago.
+// You could write a Python script to fake an RSA signature for any
+// certificate. We find new instances of it every other year or so.
+//
+// RSA with an encrypting exponent of 3 is popular, because it makes the RSA
+// math faster.
+//
+// With e=3 RSA, encryption is just cubing a number mod the public encryption
+// modulus:
+//
+//	c = m ** 3 % n
+//
+// e=3 is secure as long as we can make assumptions about the message blocks
+// we're encrypting. The worry with low-exponent RSA is that the message blocks
+// we process won't be large enough to wrap the modulus after being cubed. The
+// block 00:02 (imagine sufficient zero-padding) can be "encrypted" in e=3 RSA;
+// it is simply 00:08.
+//
+// When RSA is used to sign, rather than encrypt, the operations are reversed;
+// the verifier "decrypts" the message by cubing it. This produces a
+// "plaintext" which the verifier checks for validity.
+//
+// When you RSA sign a message, you supply it a block input that contains a
+// message digest. The PKCS1.5 standard formats that block as:
+//
+//	00h 01h ffh ffh ... ffh ffh 00h ASN.1 GOOP HASH
+//
+// As intended, the ffh bytes in that block expand to fill the whole block,
+// producing a "right-justified" hash (the last byte of the hash is the last
+// byte of the message).
+//
+// There was, 7 years ago, a common bug with PKCS1.5 verifiers. They wouldn't
+// check all the padding bytes:
+//
+//	00h 01h ffh ffh ... ffh ffh 00h ASN.1 GOOP HASH
+//	                                     ^^^^^^^^^^^^
+//	                                     these would be checked
+//	    ^^^^^^^^^^^^^^^^^^^^^^^
+//	    but not how long this is
+//
+// This means you could produce a block like this:
+//
+//	00h 01h ffh ffh ffh ffh 00h ASN.1 GOOP HASH GARBAGE
+//
+// and the verifier would accept it, provided the GARBAGE is in the right
+// place. As long as the hash that appears after the ASN.1 GOOP matches, the
+// signature validates.
+//
+// If you can find a number that when cubed produces a block that looks like
+// this, with the garbage at the end, you've forged a signature. And it turns
+// out that since the garbage can be anything, it's easy to find such a number:
+// just take the cube root of the block you want (with the garbage bits set
+// however is convenient) and round up. Hal Finney wrote up the details.
+//
+// Forge a 1024-bit RSA signature for the string "hi mom". Make sure your
+// implementation actually accepts the signature!
+
+package set6
+
+import (
+	"fmt"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// ForgeE3Signature forges a PKCS#1 v1.5 SHA-1 signature of message under an
+// e=3 public key without knowledge of the private key. The forgery is only
+// accepted by verifiers that do not check the length of the padding, such as
+// rsa.VerifyPKCS1v15Sloppy.
+func ForgeE3Signature(pub *rsa.PublicKey, message []byte) ([]byte, error) {
+	if pub.E != 3 {
+		return nil, fmt.Errorf("want e = 3, got %d", pub.E)
+	}
+	sig, err := attack.ForgePKCS1v15Signature(pub, message)
+	if err != nil {
+		return nil, fmt.Errorf("forging signature: %w", err)
+	}
+	return sig, nil
+}
@@ -0,0 +1,29 @@
+package set6
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestChallenge42(t *testing.T) {
+	priv, err := rsa.GenerateKey(rand.Reader, 1024, 3)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	message := []byte("hi mom")
+
+	sig, err := ForgeE3Signature(&priv.PublicKey, message)
+	if err != nil {
+		t.Fatalf("forging signature: %v", err)
+	}
+
+	if err := rsa.VerifyPKCS1v15Sloppy(&priv.PublicKey, message, sig); err != nil {
+		t.Fatalf("want forged signature to verify, got: %v", err)
+	}
+	if err := rsa.VerifyPKCS1v15(&priv.PublicKey, message, sig); !errors.Is(err, rsa.ErrVerification) {
+		t.Fatalf("want strict verifier to reject forged signature with: %v, got: %v", rsa.ErrVerification, err)
+	}
+}
@@ -0,0 +1,44 @@
+package set6
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"runtime"
+	"testing"
+
+	"github.com/saclark/cryptopals/dsa"
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/sha1"
+)
+
+func TestChallenge43(t *testing.T) {
+	y, _ := new(big.Int).SetString("84ad4719d044495496a3201c8ff484feb45b962e7302e56a392aee4"+
+		"abab3e4bdebf2955b4736012f21a08084056b19bcd7fee56048e004"+
+		"e44984e2f411788efdc837a0d2e5abb7b555039fd243ac01f0fb2ed"+
+		"1dec568280ce678e931868d23eb095fde9d3779191b8c0299d6e07b"+
+		"bb283e6633451e535c45513b2d33c99ea17", 16)
+	r, _ := new(big.Int).SetString("548099063082341131477253921760299949438196259240", 10)
+	s, _ := new(big.Int).SetString("857042759984254168557880549501802188789837994940", 10)
+	message := []byte("For those that envy a MC it can be hazardous to your health\n" +
+		"So be friendly, a matter of life and death, just like a etch-a-sketch\n")
+
+	if got, want := sha1.Sum(message), "d2d0714f014a9784047eaeccf956520045c45265"; hex.EncodeToString(got[:]) != want {
+		t.Fatalf("message hash: want: '%s', got: '%x'", want, got)
+	}
+
+	var fingerprint sha1.Digest
+	copy(fingerprint[:], testutil.Must(hex.DecodeString("0954edd5e0afe5542a4adf012611a91912a3ec16")))
+
+	priv, err := RecoverDSAKeyFrom16BitNonce(context.Background(), message, r, s, fingerprint, runtime.NumCPU(), t.Logf)
+	if err != nil {
+		t.Fatalf("recovering private key: %v", err)
+	}
+
+	if y.Cmp(priv.Y) != 0 {
+		t.Fatalf("want public key: %x, got: %x", y, priv.Y)
+	}
+	if err := dsa.Verify(&priv.PublicKey, message, r, s); err != nil {
+		t.Fatalf("verifying signature with recovered public key: %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package set6
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/dsa"
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/sha1"
+)
+
+const (
+	challenge44YHex        = "2d026f4bf30195ede3a088da85e398ef869611d0f68f0713d51c9c1a3a26c95105d915e2d8cdf26d056b86b8a7b85519b1c23cc3ecdc6062650462e3063bd179c2a6581519f674a61f1d89a1fff27171ebc1b93d4dc57bceb7ae2430f98a6a4d83d8279ee65d71c1203d2c96d65ebbf7cce9d32971c3de5084cce04a2e147821"
+	challenge44Fingerprint = "ca8f6f7c66fa362d40760d135b763eb8527d3d52"
+)
+
+func TestChallenge44(t *testing.T) {
+	y, _ := new(big.Int).SetString(challenge44YHex, 16)
+	records := testutil.MustParseDSASignatureFile("data/44.txt")
+
+	signed := make([]attack.DSASignedMessage, len(records))
+	for i, rec := range records {
+		if sum := sha1.Sum(rec.Message); new(big.Int).SetBytes(sum[:]).Cmp(rec.M) != 0 {
+			t.Fatalf("record %d: want m: '%x', got: '%x'", i, sum, rec.M)
+		}
+		signed[i] = attack.DSASignedMessage{Message: rec.Message, R: rec.R, S: rec.S}
+	}
+
+	priv, err := RecoverDSAKeyFromCorpus(y, signed)
+	if err != nil {
+		t.Fatalf("recovering private key: %v", err)
+	}
+
+	fingerprint := dsa.Fingerprint(priv.X)
+	if got := hex.EncodeToString(fingerprint[:]); got != challenge44Fingerprint {
+		t.Fatalf("want: '%s', got: '%s'", challenge44Fingerprint, got)
+	}
+	for i, rec := range signed {
+		if err := dsa.Verify(&priv.PublicKey, rec.Message, rec.R, rec.S); err != nil {
+			t.Fatalf("verifying record %d with recovered key: %v", i, err)
+		}
+	}
+}
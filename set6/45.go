@@ -0,0 +1,51 @@
+// # DSA parameter tampering
+//
+// Take your DSA code from the previous exercise. Imagine it as part of an
+// algorithm in which the client was allowed to propose domain parameters (the p
+// and q moduli, and the g generator).
+//
+// This would be bad, because attackers could trick victims into accepting bad
+// parameters. Vaudenay gave two examples of bad generator parameters:
+// generators that were 0 mod p, and generators that were 1 mod p.
+//
+// Use the parameters from the previous exercise, but substitute 0 for "g".
+// Generate a signature. You will notice something bad. Verify the signature.
+// Now verify any other signature, for any other string.
+//
+// Now, try (p+1) as "g". With this "g", you can generate a magic signature s, r
+// for any DSA public key that will validate against any string. For arbitrary
+// z:
+//
+//	r = ((y**z) % p) % q
+//
+//	      r
+//	s =  --- % q
+//	      z
+//
+// Sign "Hello, world". And "Goodbye, world".
+
+package set6
+
+import (
+	"math/big"
+
+	"github.com/saclark/cryptopals/dsa"
+)
+
+// ZeroGDSAParameters returns the cryptopals DSA parameters with G replaced by
+// 0. Every signature generated under them has r = 0, which verifiers that do
+// not check that r is in (0, Q) will accept for any message.
+func ZeroGDSAParameters() *dsa.Parameters {
+	params := dsa.CryptopalsParameters()
+	params.G = big.NewInt(0)
+	return params
+}
+
+// PPlusOneGDSAParameters returns the cryptopals DSA parameters with G replaced
+// by P+1, under which attack.ForgeDSAMagicSignature can forge a signature that
+// verifies for any message.
+func PPlusOneGDSAParameters() *dsa.Parameters {
+	params := dsa.CryptopalsParameters()
+	params.G = new(big.Int).Add(params.P, big.NewInt(1))
+	return params
+}
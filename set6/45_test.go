@@ -0,0 +1,55 @@
+package set6
+
+import (
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/dsa"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+func TestChallenge45_GEqualsZero(t *testing.T) {
+	priv, err := dsa.GenerateKey(ZeroGDSAParameters(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	r, s, err := dsa.SignLenient(priv, rand.Reader, []byte("Hello, world"))
+	if err != nil {
+		t.Fatalf("signing: %v", err)
+	}
+	if r.Sign() != 0 {
+		t.Fatalf("want r: 0, got: %d", r)
+	}
+
+	for _, message := range []string{"Hello, world", "Goodbye, world"} {
+		if err := dsa.VerifyLenient(&priv.PublicKey, []byte(message), r, s); err != nil {
+			t.Fatalf("lenient verifier: want '%s' to verify, got: %v", message, err)
+		}
+		if err := dsa.Verify(&priv.PublicKey, []byte(message), r, s); !errors.Is(err, dsa.ErrVerification) {
+			t.Fatalf("strict verifier: want '%s' to fail with: %v, got: %v", message, dsa.ErrVerification, err)
+		}
+	}
+}
+
+func TestChallenge45_GEqualsPPlusOne(t *testing.T) {
+	priv, err := dsa.GenerateKey(PPlusOneGDSAParameters(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	z := new(big.Int).Add(testutil.Must(rand.Int(rand.Reader, big.NewInt(1<<32))), big.NewInt(1))
+	r, s, err := attack.ForgeDSAMagicSignature(&priv.PublicKey, z)
+	if err != nil {
+		t.Fatalf("forging signature: %v", err)
+	}
+
+	for _, message := range []string{"Hello, world", "Goodbye, world"} {
+		if err := dsa.Verify(&priv.PublicKey, []byte(message), r, s); err != nil {
+			t.Fatalf("want '%s' to verify, got: %v", message, err)
+		}
+	}
+}
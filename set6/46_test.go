@@ -0,0 +1,42 @@
+package set6
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestChallenge46(t *testing.T) {
+	oracle := NewParityOracle(1024)
+	want := testutil.MustBase64DecodeString("VGhhdCdzIHdoeSBJIGZvdW5kIHlvdSBkb24ndCBwbGF5IGFyb3VuZCB3aXRoIHRoZSBGdW5reSBDb2xkIE1lZGluYQ==")
+	ciphertext := oracle.Encrypt(want)
+
+	got := DecryptWithParityOracle(&oracle.Key.PublicKey, ciphertext, oracle.IsEven, t.Logf)
+
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+}
+
+// ParityOracle decrypts RSA ciphertexts with a private key and reveals only
+// whether the plaintext is even.
+type ParityOracle struct {
+	Key *rsa.PrivateKey
+}
+
+func NewParityOracle(bits int) *ParityOracle {
+	return &ParityOracle{Key: testutil.Must(rsa.GenerateKey(rand.Reader, bits, 65537))}
+}
+
+func (o *ParityOracle) Encrypt(plaintext []byte) []byte {
+	c := testutil.Must(rsa.Encrypt(&o.Key.PublicKey, rsa.BytesToInt(plaintext)))
+	return rsa.IntToBytes(c, o.Key.Size())
+}
+
+func (o *ParityOracle) IsEven(ciphertext []byte) bool {
+	m := testutil.Must(rsa.Decrypt(o.Key, rsa.BytesToInt(ciphertext)))
+	return m.Bit(0) == 0
+}
@@ -0,0 +1,83 @@
+// # Bleichenbacher's PKCS 1.5 Padding Oracle (Simple Case)
+//
+// Degree of difficulty: moderate
+//
+// These next two challenges are the hardest in the entire set.
+//
+// Let us Google this for you: "Chosen ciphertext attacks against protocols
+// based on the RSA encryption standard"
+//
+// This is Bleichenbacher from CRYPTO '98; I get a bunch of .ps versions on
+// the first search page.
+//
+// Read the paper. It describes a padding oracle attack on PKCS#1v1.5. The
+// attack is similar in spirit to the CBC padding oracle you built earlier;
+// it's an "adaptive chosen ciphertext attack", which means you start with a
+// valid ciphertext and repeatedly corrupt it, bouncing the adulterated
+// ciphertexts off the target to learn things about the original.
+//
+// This is a common flaw even in modern cryptosystems that use RSA.
+//
+// It's also the most fun you can have building a crypto attack. It involves 9th
+// grade math, but also has you implementing an algorithm that is complex on par
+// with finding a minimum cost spanning tree.
+//
+// The setup:
+//
+//   - Build an oracle function, just like you did in the last exercise, but
+//     have it check for plaintext[0] == 0 and plaintext[1] == 2.
+//   - Generate a 256 bit keypair (that is, p and q will each be 128 bit
+//     primes), [n, e, d].
+//   - Plug d and n into your oracle function.
+//   - PKCS1.5-pad a short message, like "kick it, CC", and call it "m".
+//     Encrypt to to get "c".
+//   - Decrypt "c" using your padding oracle.
+//
+// For this challenge, we've used an untypically short RSA modulus, to make
+// the algorithm easier to debug and implement. You don't have to handle the
+// multiple-intervals case of step 2b, and you won't need to implement the
+// full intervals-merging step of step 3.
+//
+// You are now ready to implement the attack. We'll begin by the case where
+// only one interval is maintained. In the next challenge, we'll let you
+// deal with the general case.
+//
+// Do yourself a favor and get a working version of the simple case before
+// you dig deep into the complete case.
+
+package set6
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// DecryptWithPKCS1v15PaddingOracle decrypts a PKCS#1 v1.5 padded RSA
+// ciphertext using an oracle that reports whether the plaintext of a
+// ciphertext begins with 0x00 0x02, and returns the unpadded message. It is the
+// RSA counterpart to set3.CrackCBCPaddingOracle. The complete attack is
+// implemented, including step 2b and interval merging, so it also solves
+// challenge 48. When not nil, logf is used to log the attack's progress.
+func DecryptWithPKCS1v15PaddingOracle(
+	pub *rsa.PublicKey,
+	ciphertext []byte,
+	isConforming func(ciphertext []byte) bool,
+	logf func(format string, a ...any),
+) ([]byte, error) {
+	m, err := attack.ExploitPKCS1v15PaddingOracle(pub, ciphertext, isConforming, logf)
+	if err != nil {
+		return nil, fmt.Errorf("exploiting padding oracle: %w", err)
+	}
+
+	// Strip the 0x00 0x02 || PS || 0x00 padding.
+	em := rsa.IntToBytes(m, pub.Size())
+	for i := 2; i < len(em); i++ {
+		if em[i] == 0x00 {
+			return em[i+1:], nil
+		}
+	}
+	return nil, errors.New("recovered plaintext has no padding separator")
+}
@@ -0,0 +1,57 @@
+package set6
+
+import (
+	"bytes"
+	"crypto/rand"
+	"sync/atomic"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+func TestChallenge47(t *testing.T) {
+	oracle := NewPKCS1v15PaddingOracle(256)
+	want := []byte("kick it, CC")
+	ciphertext := testutil.Must(rsa.EncryptPKCS1v15(rand.Reader, &oracle.Key.PublicKey, want))
+
+	got, err := DecryptWithPKCS1v15PaddingOracle(&oracle.Key.PublicKey, ciphertext, oracle.IsConforming, nil)
+	if err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+	calls := oracle.Calls.Load()
+	t.Logf("oracle calls: %d", calls)
+	if calls == 0 || calls > maxPKCS1v15OracleCalls {
+		t.Fatalf("want: oracle calls in [1, %d], got: %d", maxPKCS1v15OracleCalls, calls)
+	}
+}
+
+// maxPKCS1v15OracleCalls bounds the number of oracle calls the attack may make.
+// The searches of steps 2a and 2b dominate, and take about as many calls for
+// a 768-bit modulus as for a 256-bit one. Most runs take a few tens of
+// thousands of calls, but the tail is long: the slowest of 1500 runs against
+// 256-bit keys took about a million.
+const maxPKCS1v15OracleCalls = 1 << 22
+
+// PKCS1v15PaddingOracle decrypts RSA ciphertexts with a private key and reveals
+// only whether the plaintext is PKCS#1 v1.5 conforming, that is, whether it
+// begins with 0x00 0x02. It counts the number of times it has been queried.
+type PKCS1v15PaddingOracle struct {
+	Key   *rsa.PrivateKey
+	Calls atomic.Int64
+}
+
+func NewPKCS1v15PaddingOracle(bits int) *PKCS1v15PaddingOracle {
+	return &PKCS1v15PaddingOracle{Key: testutil.Must(rsa.GenerateKey(rand.Reader, bits, 3))}
+}
+
+func (o *PKCS1v15PaddingOracle) IsConforming(ciphertext []byte) bool {
+	o.Calls.Add(1)
+	m := testutil.Must(rsa.Decrypt(o.Key, rsa.BytesToInt(ciphertext)))
+	em := rsa.IntToBytes(m, o.Key.Size())
+	return em[0] == 0x00 && em[1] == 0x02
+}
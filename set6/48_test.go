@@ -0,0 +1,39 @@
+package set6
+
+import (
+	"bytes"
+	"crypto/rand"
+	"flag"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/rsa"
+)
+
+// The modulus size in bits. The default is small enough for the test to run in
+// about a second. The challenge itself uses a 768-bit modulus, which usually
+// takes several seconds and occasionally tens of seconds, since the number of
+// oracle calls needed varies widely between keys. To run it:
+//
+//	go test ./set6 -run 48 -chal48 768
+var chal48Bits = flag.Int("chal48", 192, "Modulus size in bits for challenge 48")
+
+func TestChallenge48(t *testing.T) {
+	oracle := NewPKCS1v15PaddingOracle(*chal48Bits)
+	want := []byte("kick it, CC")
+	ciphertext := testutil.Must(rsa.EncryptPKCS1v15(rand.Reader, &oracle.Key.PublicKey, want))
+
+	got, err := DecryptWithPKCS1v15PaddingOracle(&oracle.Key.PublicKey, ciphertext, oracle.IsConforming, nil)
+	if err != nil {
+		t.Fatalf("decrypting: %v", err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Fatalf("want: '%s', got: '%s'", want, got)
+	}
+	calls := oracle.Calls.Load()
+	t.Logf("oracle calls: %d", calls)
+	if calls > maxPKCS1v15OracleCalls {
+		t.Fatalf("want: at most %d oracle calls, got: %d", maxPKCS1v15OracleCalls, calls)
+	}
+}
@@ -0,0 +1,169 @@
+// # CBC-MAC Message Forgery
+//
+// Let's talk about CBC-MAC.
+//
+// CBC-MAC is like this:
+//
+//  1. Take the plaintext P.
+//  2. Encrypt P under CBC with key K, yielding ciphertext C.
+//  3. Chuck all of C but the last block C[n].
+//  4. C[n] is the MAC.
+//
+// Suppose there's an online banking application, and it carries out user
+// requests by talking to an API server over the network. Each request looks
+// like this:
+//
+//	message || IV || MAC
+//
+// The message looks like this:
+//
+//	from=#{from_id}&to=#{to_id}&amount=#{amount}
+//
+// Now, write an API server and a web frontend for it. (NOTE: No need to get
+// ambitious and write actual servers and web apps. Totally fine to go lo-fi on
+// this one.) The client and server should share a secret key K to sign and
+// verify messages.
+//
+// The API server should accept messages, verify signatures, and carry out
+// each transaction if the MAC is valid. It's also publicly exposed - the
+// attacker can submit messages freely assuming he can forge the right MAC.
+//
+// The web client should allow the attacker to generate valid messages for
+// accounts he controls. (Feel free to sanitize params if you're feeling
+// anal-retentive.) Assume the attacker is in a position to capture and inspect
+// messages from the client to the API server.
+//
+// One thing we haven't discussed is the IV. Assume the client generates a
+// per-message IV and sends it along with the MAC. That's how CBC works, right?
+//
+// Wrong.
+//
+// For messages signed under CBC-MAC, an attacker-controlled IV is a liability.
+// Why? Because yadda yadda yadda.
+//
+// Your mission: Assume you know the victim's account ID (i.e. it's public).
+// Generate a valid message for your own account, then use your control over
+// the IV to forge a message that transfers 1M spacebucks from the victim's
+// account into yours.
+//
+// Don't peek at the next paragraph until you've done this.
+//
+// OK, now that you've done that, assume the client and server share a fixed
+// IV (0 is fine). This means the attacker can't control the IV anymore. Go
+// ahead and rewrite the server to support multiple transactions per message:
+//
+//	from=#{from_id}&tx_list=#{transactions}
+//
+// It now looks like this:
+//
+//	message || MAC
+//
+// Like before, the attacker can generate messages and capture messages from
+// the client. Also like before, the victim's account ID is public.
+//
+// Your mission: Capture a valid message from your target user. Use length
+// extension to add a transaction paying the attacker's account 1M spacebucks.
+//
+// Hint!
+//
+// This would be a lot easier if you had full control over the first block of
+// your message, huh? Maybe you can simulate that.
+//
+// Food for thought: How would you modify the protocol to prevent this?
+
+package set7
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/saclark/cryptopals/attack"
+)
+
+// ForgeTransferRequest forges a request of the form message || IV || MAC,
+// where message is from=#{from_id}&to=#{to_id}&amount=#{amount}, that
+// transfers the same amount to the same account as request but from the
+// account fromID. The new account ID must be the same length as the original
+// and fall within the first block of the message.
+func ForgeTransferRequest(request []byte, fromID string) ([]byte, error) {
+	if len(request) < 2*aes.BlockSize {
+		return nil, errors.New("request too short")
+	}
+	message := request[:len(request)-2*aes.BlockSize]
+	iv := request[len(request)-2*aes.BlockSize : len(request)-aes.BlockSize]
+	mac := request[len(request)-aes.BlockSize:]
+
+	rest, ok := bytes.CutPrefix(message, []byte("from="))
+	if !ok {
+		return nil, errors.New("message does not start with 'from='")
+	}
+	i := bytes.IndexByte(rest, '&')
+	if i != len(fromID) {
+		return nil, fmt.Errorf("account ID length %d differs from original length %d", len(fromID), i)
+	}
+	forged := append([]byte("from="+fromID), rest[i:]...)
+
+	forgedIV, err := attack.ForgeCBCMACIV(message, iv, forged)
+	if err != nil {
+		return nil, fmt.Errorf("forging IV: %w", err)
+	}
+
+	forged = append(forged, forgedIV...)
+	return append(forged, mac...), nil
+}
+
+// ForgeTxListRequest forges a request of the form message || MAC, where the
+// MAC uses a fixed, all-zero IV, by appending the transactions of the
+// attacker's request to those of the victim's. The attacker's first block is
+// garbled by the splice, so the transactions that matter must come after it,
+// following a throwaway transaction, as in
+//
+//	from=#{attacker_id}&tx_list=#{attacker_id}:1;#{attacker_id}:1000000
+//
+// where the first block ends partway through the throwaway transaction.
+func ForgeTxListRequest(victimRequest, attackerRequest []byte) ([]byte, error) {
+	if len(victimRequest) < aes.BlockSize || len(attackerRequest) < aes.BlockSize {
+		return nil, errors.New("request too short")
+	}
+	victimMessage := victimRequest[:len(victimRequest)-aes.BlockSize]
+	victimMAC := victimRequest[len(victimRequest)-aes.BlockSize:]
+	attackerMessage := attackerRequest[:len(attackerRequest)-aes.BlockSize]
+	attackerMAC := attackerRequest[len(attackerRequest)-aes.BlockSize:]
+
+	iv := make([]byte, aes.BlockSize)
+	forged, err := attack.ExtendCBCMAC(iv, victimMessage, victimMAC, attackerMessage)
+	if err != nil {
+		return nil, fmt.Errorf("extending message: %w", err)
+	}
+
+	return append(forged, attackerMAC...), nil
+}
+
+// NewBankRequestFunc returns a function that submits a signed request to the
+// bank API at the given URL. It returns an error if the request is rejected.
+func NewBankRequestFunc(client *http.Client, url string) func(ctx context.Context, request []byte) error {
+	return func(ctx context.Context, request []byte) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(request))
+		if err != nil {
+			return fmt.Errorf("building request: %w", err)
+		}
+		req.Header.Add("Content-Type", "application/octet-stream")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("sending request: %w", err)
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("unexpected response status code: %d", resp.StatusCode)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,215 @@
+package set7
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/saclark/cryptopals/cbcmac"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+const (
+	challenge49VictimID   = "2"
+	challenge49AttackerID = "4"
+)
+
+func TestChallenge49_AttackerControlledIV(t *testing.T) {
+	bank := NewCBCMACBank(map[string]int{challenge49VictimID: 2000000, challenge49AttackerID: 0})
+	ts := httptest.NewServer(bank)
+	defer ts.Close()
+	submit := NewBankRequestFunc(ts.Client(), ts.URL+"/v1/transfer")
+
+	// The web client will happily sign transfers from the attacker's own
+	// account.
+	request := bank.SignTransfer(challenge49AttackerID, challenge49AttackerID, 1000000)
+
+	forged, err := ForgeTransferRequest(request, challenge49VictimID)
+	if err != nil {
+		t.Fatalf("forging request: %v", err)
+	}
+	if err := submit(context.Background(), forged); err != nil {
+		t.Fatalf("submitting forged request: %v", err)
+	}
+
+	if got := bank.Balance(challenge49AttackerID); got != 1000000 {
+		t.Fatalf("want attacker balance: %d, got: %d", 1000000, got)
+	}
+	if got := bank.Balance(challenge49VictimID); got != 1000000 {
+		t.Fatalf("want victim balance: %d, got: %d", 1000000, got)
+	}
+}
+
+func TestChallenge49_FixedIV(t *testing.T) {
+	bank := NewCBCMACBank(map[string]int{challenge49VictimID: 2000000, challenge49AttackerID: 0, "3": 0})
+	ts := httptest.NewServer(bank)
+	defer ts.Close()
+	submit := NewBankRequestFunc(ts.Client(), ts.URL+"/v2/transfer")
+
+	// Capture a legitimate request from the victim.
+	victimRequest := bank.SignTxList(challenge49VictimID, "3:100")
+	if err := submit(context.Background(), victimRequest); err != nil {
+		t.Fatalf("submitting victim request: %v", err)
+	}
+
+	txList := fmt.Sprintf("%s:1;%s:1000000", challenge49AttackerID, challenge49AttackerID)
+	attackerRequest := bank.SignTxList(challenge49AttackerID, txList)
+
+	forged, err := ForgeTxListRequest(victimRequest, attackerRequest)
+	if err != nil {
+		t.Fatalf("forging request: %v", err)
+	}
+	if err := submit(context.Background(), forged); err != nil {
+		t.Fatalf("submitting forged request: %v", err)
+	}
+
+	if got := bank.Balance(challenge49AttackerID); got != 1000000 {
+		t.Fatalf("want attacker balance: %d, got: %d", 1000000, got)
+	}
+}
+
+// CBCMACBank is a bank API that carries out transfers signed with CBC-MAC.
+// Version 1 of the API accepts message || IV || MAC, where message is
+// from=#{from_id}&to=#{to_id}&amount=#{amount}. Version 2 accepts
+// message || MAC, using a fixed IV, where message is
+// from=#{from_id}&tx_list=#{to_id:amount(;to_id:amount)*}.
+//
+// Version 2 messages are parsed loosely: everything after the first
+// "&tx_list=" is taken to be the transaction list, and transactions that fail
+// to parse are ignored.
+type CBCMACBank struct {
+	block    cipher.Block
+	mu       sync.Mutex
+	balances map[string]int
+}
+
+func NewCBCMACBank(balances map[string]int) *CBCMACBank {
+	return &CBCMACBank{
+		block:    testutil.Must(aes.NewCipher(testutil.MustRandomBytes(aes.BlockSize))),
+		balances: balances,
+	}
+}
+
+func (b *CBCMACBank) Balance(id string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.balances[id]
+}
+
+// SignTransfer plays the role of the web client, signing a version 1 request.
+func (b *CBCMACBank) SignTransfer(from, to string, amount int) []byte {
+	message := []byte(fmt.Sprintf("from=%s&to=%s&amount=%d", from, to, amount))
+	iv := testutil.MustRandomBytes(aes.BlockSize)
+	request := append(message, iv...)
+	return append(request, cbcmac.Sum(b.block, iv, message)...)
+}
+
+// SignTxList plays the role of the web client, signing a version 2 request.
+func (b *CBCMACBank) SignTxList(from, txList string) []byte {
+	message := []byte(fmt.Sprintf("from=%s&tx_list=%s", from, txList))
+	return append(message, cbcmac.Sum(b.block, make([]byte, aes.BlockSize), message)...)
+}
+
+func (b *CBCMACBank) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Content-Type") != "application/octet-stream" {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	request, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v1/transfer":
+		b.handleTransfer(w, request)
+	case "/v2/transfer":
+		b.handleTxList(w, request)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (b *CBCMACBank) handleTransfer(w http.ResponseWriter, request []byte) {
+	if len(request) < 2*aes.BlockSize {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	message := request[:len(request)-2*aes.BlockSize]
+	iv := request[len(request)-2*aes.BlockSize : len(request)-aes.BlockSize]
+	mac := request[len(request)-aes.BlockSize:]
+	if !cbcmac.Verify(b.block, iv, message, mac) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	params := parseParams(string(message))
+	amount, err := strconv.Atoi(params["amount"])
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	b.transfer(params["from"], params["to"], amount)
+}
+
+func (b *CBCMACBank) handleTxList(w http.ResponseWriter, request []byte) {
+	if len(request) < aes.BlockSize {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	message := request[:len(request)-aes.BlockSize]
+	mac := request[len(request)-aes.BlockSize:]
+	if !cbcmac.Verify(b.block, make([]byte, aes.BlockSize), message, mac) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	fromParam, txList, ok := strings.Cut(string(message), "&tx_list=")
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	from := strings.TrimPrefix(fromParam, "from=")
+	for _, tx := range strings.Split(txList, ";") {
+		to, amountStr, ok := strings.Cut(tx, ":")
+		if !ok {
+			continue
+		}
+		amount, err := strconv.Atoi(amountStr)
+		if err != nil {
+			continue
+		}
+		b.transfer(from, to, amount)
+	}
+}
+
+func (b *CBCMACBank) transfer(from, to string, amount int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.balances[from] -= amount
+	b.balances[to] += amount
+}
+
+// parseParams parses a string of the form k1=v1&k2=v2. Later values of
+// repeated keys win.
+func parseParams(s string) map[string]string {
+	params := make(map[string]string)
+	for _, kv := range strings.Split(s, "&") {
+		k, v, _ := strings.Cut(kv, "=")
+		params[k] = v
+	}
+	return params
+}
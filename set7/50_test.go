@@ -0,0 +1,37 @@
+package set7
+
+import (
+	"bytes"
+	"crypto/aes"
+	"testing"
+
+	"github.com/saclark/cryptopals/cbcmac"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+func TestChallenge50(t *testing.T) {
+	key := []byte("YELLOW SUBMARINE")
+	block := testutil.Must(aes.NewCipher(key))
+	iv := make([]byte, aes.BlockSize)
+	target := testutil.MustHexDecodeString("296b8d7cb78a243dda4d0a61d33bbdd1")
+
+	if got := cbcmac.Sum(block, iv, []byte("alert('MZA who was that?');\n")); !bytes.Equal(target, got) {
+		t.Fatalf("original snippet: want: '%x', got: '%x'", target, got)
+	}
+
+	snippet := "alert('Ayo, the Wu is back!');"
+	forged, err := ForgeJavaScriptSnippet(key, target, snippet)
+	if err != nil {
+		t.Fatalf("forging snippet: %v", err)
+	}
+
+	if !bytes.HasPrefix(forged, []byte(snippet)) {
+		t.Fatalf("want prefix: '%s', got: %q", snippet, forged)
+	}
+	if bytes.ContainsAny(forged, "\r\n") {
+		t.Fatalf("want no line terminators, got: %q", forged)
+	}
+	if got := cbcmac.Sum(block, iv, forged); !bytes.Equal(target, got) {
+		t.Fatalf("want: '%x', got: '%x'", target, got)
+	}
+}
@@ -0,0 +1,117 @@
+// # Compression Ratio Side-Channel Attacks
+//
+// Internet traffic is often compressed to save bandwidth. Until recently, this
+// included HTTPS headers, and it still includes the contents of responses.
+//
+// Why does that matter?
+//
+// Well, if you're an attacker with:
+//
+// 1. Partial plaintext knowledge and
+//
+// 2. Partial plaintext control and
+//
+// 3. Access to a compression oracle
+//
+// You've got a pretty good chance to recover any additional unknown plaintext.
+//
+// What's a compression oracle? You give it some input and it tells you how well
+// the full message compresses, i.e. the length of the resultant output.
+//
+// This is somewhat similar to the timing attacks we did way back in set 4 in
+// that we're taking advantage of incidental side channels rather than attacking
+// the cryptographic mechanisms themselves.
+//
+// Scenario: you are running a MITM attack with an eye towards stealing secure
+// session cookies. You've injected malicious content allowing you to spawn
+// arbitrary requests and observe them in flight. (This is actually not as
+// difficult as it might sound.)
+//
+// Write this oracle:
+//
+//	oracle(P) -> length(encrypt(compress(format_request(P))))
+//
+// Format the request like this:
+//
+//	POST / HTTP/1.1
+//	Host: hapless.com
+//	Cookie: sessionid=TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE=
+//	Content-Length: ((len(P)))
+//	((P))
+//
+// (Pretend you can't see that session id. You're the attacker.)
+//
+// Use zlib or whatever to compress the request.
+//
+// Encrypt it with... actually, it doesn't really matter. Just use a stream
+// cipher. Extra fun: use a random key/IV on each call to the oracle.
+//
+// And then just return the length in bytes.
+//
+// Now, the idea here is to leak information using the compression library. A
+// payload of "sessionid=T" should compress just a little bit better than, say,
+// "sessionid=S".
+//
+// There is one complicating factor. The DEFLATE algorithm operates in terms of
+// individual bits, but the final message length will be in bytes. Even if you
+// do find a better compression, the difference may not cross a byte boundary.
+// So that's a problem.
+//
+// You may also get some incorrect guesses that happen to compress better than
+// the correct one. Be sure you have a mechanism to handle that.
+//
+// Once you've cracked the stream cipher version, switch to CBC and do it again.
+//
+// Hint: the block cipher can be thought of as an amplifier: it'll eat up
+// compressed differences that don't cross a block boundary, so you'll want to
+// pad your guesses out such that the compressed output is just about to cross
+// into a new block.
+
+package set7
+
+import (
+	"fmt"
+
+	"github.com/saclark/cryptopals/attack"
+)
+
+// The session ID is base64 encoded and ends at the line break that follows it.
+const (
+	sessionIDPrefix   = "sessionid="
+	sessionIDAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=\n"
+	maxSessionIDLen   = 128
+)
+
+// RecoverSessionIDFromStreamCompressionOracle recovers the session ID cookie
+// from a request that is compressed and then encrypted with a stream cipher,
+// using an oracle that returns the length of the encrypted request when it
+// includes a chosen payload.
+//
+// When not nil, logf is used to log the session ID recovered so far.
+func RecoverSessionIDFromStreamCompressionOracle(oracle func(payload []byte) int, logf func(format string, a ...any)) (string, error) {
+	return recoverSessionID(oracle, 1, logf)
+}
+
+// RecoverSessionIDFromBlockCompressionOracle is like
+// RecoverSessionIDFromStreamCompressionOracle, except that the request is
+// encrypted with a block cipher, such as AES in CBC mode, with a block size of
+// at most 16 bytes.
+func RecoverSessionIDFromBlockCompressionOracle(oracle func(payload []byte) int, logf func(format string, a ...any)) (string, error) {
+	return recoverSessionID(oracle, 16, logf)
+}
+
+func recoverSessionID(oracle func(payload []byte) int, blockSize int, logf func(format string, a ...any)) (string, error) {
+	id, err := attack.ExploitCompressionOracle(
+		oracle,
+		[]byte(sessionIDPrefix),
+		[]byte(sessionIDAlphabet),
+		'\n',
+		maxSessionIDLen,
+		blockSize,
+		logf,
+	)
+	if err != nil {
+		return "", fmt.Errorf("exploiting compression oracle: %w", err)
+	}
+	return string(id), nil
+}
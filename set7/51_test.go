@@ -0,0 +1,69 @@
+package set7
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"fmt"
+	"testing"
+
+	"github.com/saclark/cryptopals/cipher"
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/pkcs7"
+)
+
+const challenge51SessionID = "TmV2ZXIgcmV2ZWFsIHRoZSBXdS1UYW5nIFNlY3JldCE="
+
+func TestChallenge51_CTR(t *testing.T) {
+	got, err := RecoverSessionIDFromStreamCompressionOracle(CompressionOracleCTR, t.Logf)
+	if err != nil {
+		t.Fatalf("recovering session ID: %v", err)
+	}
+	if got != challenge51SessionID {
+		t.Fatalf("want: '%s', got: '%s'", challenge51SessionID, got)
+	}
+}
+
+func TestChallenge51_CBC(t *testing.T) {
+	got, err := RecoverSessionIDFromBlockCompressionOracle(CompressionOracleCBC, t.Logf)
+	if err != nil {
+		t.Fatalf("recovering session ID: %v", err)
+	}
+	if got != challenge51SessionID {
+		t.Fatalf("want: '%s', got: '%s'", challenge51SessionID, got)
+	}
+}
+
+// CompressionOracleCTR compresses a request containing payload, encrypts it
+// under CTR with a random key and nonce, and returns the ciphertext's length.
+func CompressionOracleCTR(payload []byte) int {
+	compressed := compressRequest(payload)
+	key := testutil.MustRandomBytes(aes.BlockSize)
+	nonce := testutil.MustRandomBytes(aes.BlockSize)
+	return len(testutil.Must(cipher.CTRCrypt(compressed, key, nonce)))
+}
+
+// CompressionOracleCBC compresses a request containing payload, encrypts it
+// under CBC with a random key and IV, and returns the ciphertext's length.
+func CompressionOracleCBC(payload []byte) int {
+	compressed := pkcs7.Pad(compressRequest(payload), aes.BlockSize)
+	key := testutil.MustRandomBytes(aes.BlockSize)
+	iv := testutil.MustRandomBytes(aes.BlockSize)
+	return len(testutil.Must(cipher.CBCEncrypt(compressed, key, iv)))
+}
+
+func compressRequest(payload []byte) []byte {
+	request := fmt.Sprintf("POST / HTTP/1.1\n"+
+		"Host: hapless.com\n"+
+		"Cookie: sessionid=%s\n"+
+		"Content-Length: %d\n"+
+		"%s", challenge51SessionID, len(payload), payload)
+
+	var buf bytes.Buffer
+	w := testutil.Must(flate.NewWriter(&buf, flate.DefaultCompression))
+	testutil.Must(w.Write([]byte(request)))
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
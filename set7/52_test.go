@@ -0,0 +1,36 @@
+package set7
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/saclark/cryptopals/md"
+)
+
+func TestChallenge52(t *testing.T) {
+	var fCalls, gCalls int
+	f := md.NewAESHash([]byte{0x13, 0x37})
+	f.Compress = countCompressCalls(f.Compress, &fCalls)
+	g := md.NewAESHash([]byte{0xC0, 0xFF, 0xEE})
+	g.Compress = countCompressCalls(g.Compress, &gCalls)
+
+	m1, m2 := FindCascadeCollision(f, g, t.Logf)
+	t.Logf("compression function calls: f: %d, g: %d", fCalls, gCalls)
+
+	if bytes.Equal(m1, m2) {
+		t.Fatalf("want distinct messages, got: '%x'", m1)
+	}
+	if f1, f2 := f.Sum(m1), f.Sum(m2); !bytes.Equal(f1, f2) {
+		t.Fatalf("f: want: '%x', got: '%x'", f1, f2)
+	}
+	if g1, g2 := g.Sum(m1), g.Sum(m2); !bytes.Equal(g1, g2) {
+		t.Fatalf("g: want: '%x', got: '%x'", g1, g2)
+	}
+}
+
+func countCompressCalls(compress md.CompressFunc, calls *int) md.CompressFunc {
+	return func(state, block []byte) []byte {
+		*calls++
+		return compress(state, block)
+	}
+}
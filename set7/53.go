@@ -0,0 +1,91 @@
+// # Kelsey and Schneier's Expandable Messages
+//
+// One of the basic yardsticks we use to judge a cryptographic hash function is
+// its resistance to second preimage attacks. That means that if I give you x
+// and y such that H(x) = y, you should have a tough time finding x' such that
+// H(x') = H(x).
+//
+// How tough? Brute-force tough. For a 2^b hash function, we want second
+// preimage attacks to cost 2^b operations.
+//
+// This turns out not to be the case for very long messages.
+//
+// Consider the problem we're trying to solve: we want to find a message that
+// will collide with H(x) in the very last iteration of the hash function. This
+// means that, at the end, our state will need to be exactly equal to that
+// from hashing the message x.
+//
+// But wait, there's a twist! Since we're modeling these hash functions as
+// Merkle-Damgard constructions, the final block of the message will include
+// some padding, and the padding will include the length of the message. This
+// means that our new message needs to be the same length as the target message.
+//
+// Hmm. Well, that's not so bad. Since we're hashing very long messages, we
+// have a lot of intermediate states to collide with. If we can find a single
+// block that collides with any one of these states, we can take the rest of
+// the target message and tack it on. Now we just need to find a prefix of the
+// right length that will leave us in the state before the intermediate state
+// we collided with. If only there were some way to generate messages of
+// arbitrary length that all collide.
+//
+// Why, there is!
+//
+// In fact, there's even a name for it: expandable messages.
+//
+// Let's say we want to produce a message of length L. To do so, we
+// find a set of k = log2(L) collisions. For each collision, one message will
+// be a single block long, and the other will be 2^(k-1)+1 blocks long. Build
+// it like this:
+//
+// 1. Starting from the hash function's initial state, find a collision between
+// a single-block message and a message of 2^(k-1)+1 blocks. DO NOT hash the
+// entire long message each time. Choose 2^(k-1) dummy blocks, hash those, then
+// focus on the last block.
+//
+// 2. Take the output state from the first step. Use this as your new initial
+// state and find another collision between a single-block message and a
+// message of 2^(k-2)+1 blocks.
+//
+// 3. Repeat this process k total times. Your last collision should be between
+// a single-block message and a message of 2^0+1 = 2 blocks.
+//
+// Now you can make a message of any length in (k, k + 2^k - 1) blocks by
+// choosing the appropriate message (short or long) from each pair.
+//
+// Now we're ready to attack a long message M of 2^k blocks.
+//
+// 1. Generate an expandable message of length (k, k + 2^k - 1) using the
+// strategy outlined above.
+//
+// 2. Hash M and generate a map of intermediate hash states to the block indices
+// that they correspond to.
+//
+// 3. From your expandable message's final state, find a single-block "bridge"
+// to intermediate state in your map. Note the index i it maps to.
+//
+// 4. Use your expandable message to generate a prefix of the right length such
+// that len(prefix || bridge || M[i..]) = len(M).
+//
+// The padding in the final block should now be correct, and your forgery
+// should hash to the same value as M.
+
+package set7
+
+import (
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/md"
+)
+
+// FindLongMessageSecondPreimage finds a different message with the same length
+// and hash under h as message, which should be long, using an expandable
+// message of the largest k that fits its length.
+func FindLongMessageSecondPreimage(h *md.Hash, message []byte) ([]byte, error) {
+	// Use the largest k for which the longest expansion of the expandable
+	// message, k+2^k-1 blocks, followed by a bridge block, still fits.
+	n := len(message) / h.BlockSize
+	k := 0
+	for k+1 < 63 && (k+1)+(1<<(k+1)) <= n {
+		k++
+	}
+	return attack.FindSecondPreimage(h, message, k)
+}
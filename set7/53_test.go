@@ -0,0 +1,29 @@
+package set7
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/saclark/cryptopals/internal/testutil"
+	"github.com/saclark/cryptopals/md"
+)
+
+func TestChallenge53(t *testing.T) {
+	h := md.NewAESHash([]byte{0xDE, 0xAD, 0xBE})
+	message := testutil.MustRandomBytes(1<<10*h.BlockSize + 5)
+
+	forged, err := FindLongMessageSecondPreimage(h, message)
+	if err != nil {
+		t.Fatalf("finding second preimage: %v", err)
+	}
+
+	if bytes.Equal(message, forged) {
+		t.Fatal("want a different message, got the original")
+	}
+	if len(message) != len(forged) {
+		t.Fatalf("want length: %d, got: %d", len(message), len(forged))
+	}
+	if want, got := h.Sum(message), h.Sum(forged); !bytes.Equal(want, got) {
+		t.Fatalf("want: '%x', got: '%x'", want, got)
+	}
+}
@@ -0,0 +1,194 @@
+// # Kelsey and Kohno's Nostradamus Attack
+//
+// Hash functions are sometimes used as proof of a secret prediction.
+//
+// For example, suppose you wanted to predict the score of every Major League
+// Baseball game in a season. (2,430 in all.) You might be concerned that
+// publishing your predictions would affect the outcomes.
+//
+// So instead you write down all the scores, hash the document, and publish the
+// hash. Once the season is over, you publish the document. Everyone can then
+// hash the document to verify your soothsaying prowess.
+//
+// But what if you can't accurately predict the scores of 2.4k baseball games?
+// Have no fear - forging a prediction under this scheme reduces to another
+// second preimage attack.
+//
+// We could apply the long message attack from the previous problem, but it
+// would look pretty shady. Would you trust someone whose predicted message
+// turned out to be 2^50 bytes long?
+//
+// It turns out we can run a successful attack with a much shorter suffix.
+// Check the method:
+//
+// 1. Generate a large number of initial hash states. Say, 2^k.
+//
+// 2. Pair them up and generate single-block collisions. Now you have 2^k hash
+// states that collide into 2^(k-1) states.
+//
+// 3. Repeat the process. Pair up the 2^(k-1) states and generate collisions.
+// Now you have 2^(k-2) states.
+//
+// 4. Keep doing this until you have one state. This is your prediction.
+//
+// 5. Well, sort of. You need to commit to some length to encode in the
+// padding. Make sure it's long enough to accommodate your actual message, this
+// suffix, and a little bit of glue to join them up. Hash this padding block
+// using the state from step 4 - THIS is your prediction.
+//
+// What did you just build? It's basically a funnel mapping many initial states
+// into a common final state. What's critical is we now have a big field of 2^k
+// states we can try to collide into, but the actual suffix will only be k+1
+// blocks long.
+//
+// The rest is trivial:
+//
+// 1. Wait for the end of the baseball season. (This may take some time.)
+//
+// 2. Write down the game results. Or, you know, anything else. I'm not too
+// particular.
+//
+// 3. Generate a collisions into one of the leaves of your tree. This is just
+// the glue. Your message is (results || glue || suffix), where suffix is the
+// path from the leaf you collided into to the root.
+//
+// 4. Publish your prediction.
+//
+// Have fun!
+
+package set7
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/md"
+	"github.com/saclark/cryptopals/md4"
+)
+
+// NewTruncatedMD4Hash returns a hash, built on the MD4 compression function,
+// whose state and digest are the length of iv. The state is zero padded to the
+// size of an MD4 state before each compression, and the result truncated
+// again. It panics if iv is empty or longer than an MD4 digest.
+func NewTruncatedMD4Hash(iv []byte) *md.Hash {
+	if len(iv) == 0 || len(iv) > md4.Size {
+		panic(fmt.Sprintf("invalid truncated MD4 state size: %d", len(iv)))
+	}
+	return &md.Hash{
+		BlockSize: md4.BlockSize,
+		IV:        append([]byte(nil), iv...),
+		Compress: func(state, block []byte) []byte {
+			full := make([]byte, md4.Size)
+			copy(full, state)
+			return md4.Compress(full, block)[:len(state)]
+		},
+		Pad: md.LengthPadding(md4.BlockSize, binary.LittleEndian),
+	}
+}
+
+// LoadOrBuildDiamondStructure reads the diamond structure saved at path or, if
+// there is no such file or it does not hold a structure of depth k built for h,
+// builds one and saves it there so it need not be built again.
+//
+// When not nil, logf is used to log why a saved structure is not used and the
+// progress of building a new one.
+func LoadOrBuildDiamondStructure(
+	path string,
+	h *md.Hash,
+	k int,
+	workers int,
+	logf func(format string, a ...any),
+) (*attack.DiamondStructure, error) {
+	d, err := loadDiamondStructure(path, h, k)
+	if err == nil {
+		return d, nil
+	}
+	if logf != nil && !errors.Is(err, fs.ErrNotExist) {
+		logf("rebuilding diamond structure: %v\n", err)
+	}
+
+	d = attack.BuildDiamondStructure(h, k, workers, logf)
+	if err := saveDiamondStructure(path, d); err != nil {
+		return nil, fmt.Errorf("saving diamond structure: %w", err)
+	}
+
+	return d, nil
+}
+
+// loadDiamondStructure reads the diamond structure saved at path and checks
+// that it has depth k and that each of its blocks leads to the next level's
+// state under h.
+func loadDiamondStructure(path string, h *md.Hash, k int) (*attack.DiamondStructure, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	d, err := attack.LoadDiamondStructure(f)
+	if err != nil {
+		return nil, err
+	}
+	if d.K() != k {
+		return nil, fmt.Errorf("saved diamond structure has k = %d, want %d", d.K(), k)
+	}
+	for level, blocks := range d.Blocks {
+		for j, block := range blocks {
+			if !bytes.Equal(h.Compress(d.States[level][j], block), d.States[level+1][j/2]) {
+				return nil, fmt.Errorf("saved diamond structure not built for hash: block %d of level %d", j, level)
+			}
+		}
+	}
+
+	return d, nil
+}
+
+// saveDiamondStructure writes d to a temporary file and renames it to path, so
+// that an interrupted save does not leave a truncated structure behind.
+func saveDiamondStructure(path string, d *attack.DiamondStructure) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := d.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// predictionBlocks is the number of blocks reserved for the prediction itself,
+// ahead of the glue and the path through the diamond structure.
+const predictionBlocks = 4
+
+// ErrPredictionTooLong is returned by ForgePrediction when the prediction does
+// not fit in the length committed to.
+var ErrPredictionTooLong = errors.New("prediction too long")
+
+// Prediction returns the digest to commit to for predictions herded into d.
+func Prediction(h *md.Hash, d *attack.DiamondStructure) []byte {
+	return d.Digest(h, predictionBlocks)
+}
+
+// ForgePrediction returns a message beginning with prediction whose hash under
+// h is the digest returned by Prediction for d.
+func ForgePrediction(h *md.Hash, d *attack.DiamondStructure, prediction string) ([]byte, error) {
+	if len(prediction) > predictionBlocks*h.BlockSize {
+		return nil, ErrPredictionTooLong
+	}
+	padded := make([]byte, predictionBlocks*h.BlockSize)
+	copy(padded, prediction)
+	return d.Herd(h, padded), nil
+}
@@ -0,0 +1,82 @@
+package set7
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// The depth of the diamond structure. It is saved to the temporary directory
+// and reused by later runs, since building it is the expensive part.
+var chal54K = flag.Int("chal54", 10, "Depth of the diamond structure for challenge 54")
+
+func TestChallenge54(t *testing.T) {
+	iv := []byte{0x4e, 0x6f, 0x73}
+	h := NewTruncatedMD4Hash(iv)
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("cryptopals-54-md4-%x-k%d.gob", iv, *chal54K))
+
+	d, err := LoadOrBuildDiamondStructure(path, h, *chal54K, runtime.NumCPU(), nil)
+	if err != nil {
+		t.Fatalf("loading or building diamond structure: %v", err)
+	}
+	prediction := Prediction(h, d)
+	t.Logf("prediction: %x", prediction)
+
+	results := "Cubs 8, Indians 7 (10 innings)"
+	forged, err := ForgePrediction(h, d, results)
+	if err != nil {
+		t.Fatalf("forging prediction: %v", err)
+	}
+
+	if !bytes.HasPrefix(forged, []byte(results)) {
+		t.Fatalf("want prefix: '%s', got: '%q'", results, forged)
+	}
+	if got := h.Sum(forged); !bytes.Equal(prediction, got) {
+		t.Fatalf("want: '%x', got: '%x'", prediction, got)
+	}
+
+	if _, err := ForgePrediction(h, d, string(make([]byte, predictionBlocks*h.BlockSize+1))); !errors.Is(err, ErrPredictionTooLong) {
+		t.Fatalf("want: %v, got: %v", ErrPredictionTooLong, err)
+	}
+}
+
+func TestLoadOrBuildDiamondStructure_RebuildsInvalidFile(t *testing.T) {
+	const k = 3
+	path := filepath.Join(t.TempDir(), "diamond.gob")
+	h := NewTruncatedMD4Hash([]byte{0x01, 0x02})
+
+	// A truncated file.
+	if err := os.WriteFile(path, []byte{0x2a}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d, err := LoadOrBuildDiamondStructure(path, h, k, 1, nil)
+	if err != nil {
+		t.Fatalf("loading or building diamond structure: %v", err)
+	}
+	if _, err := loadDiamondStructure(path, h, k); err != nil {
+		t.Fatalf("loading rebuilt diamond structure: %v", err)
+	}
+
+	// A structure built for a different compression function.
+	other := *h
+	other.Compress = func(state, block []byte) []byte {
+		out := h.Compress(state, block)
+		out[0] ^= 0x01
+		return out
+	}
+	if _, err := loadDiamondStructure(path, &other, k); err == nil {
+		t.Fatal("want error loading diamond structure built for another hash")
+	}
+	d, err = LoadOrBuildDiamondStructure(path, &other, k, 1, nil)
+	if err != nil {
+		t.Fatalf("loading or building diamond structure: %v", err)
+	}
+	if got := other.Sum(d.Herd(&other, []byte("prefix"))); !bytes.Equal(d.Digest(&other, 1), got) {
+		t.Fatalf("want: '%x', got: '%x'", d.Digest(&other, 1), got)
+	}
+}
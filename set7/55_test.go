@@ -0,0 +1,24 @@
+package set7
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/saclark/cryptopals/md4"
+)
+
+func TestChallenge55(t *testing.T) {
+	m1, m2, err := FindMD4CollidingMessages([]byte("and then some"), t.Logf)
+	if err != nil {
+		t.Fatalf("finding collision: %v", err)
+	}
+
+	if bytes.Equal(m1, m2) {
+		t.Fatalf("want distinct messages, got: '%x'", m1)
+	}
+	if h1, h2 := md4.Sum(m1), md4.Sum(m2); h1 != h2 {
+		t.Fatalf("want: '%x', got: '%x'", h1, h2)
+	}
+	t.Logf("M:  %x", m1)
+	t.Logf("M': %x", m2)
+}
@@ -0,0 +1,82 @@
+package set7
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/saclark/cryptopals/cipher"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+// A comma separated list of parameters denoting:
+//
+// 1. Whether the oracle should encrypt with real RC4 ('real') or with a fake
+// keystream whose biases at z16 and z32 are greatly exaggerated ('fake').
+// 2. The base 2 logarithm of the number of encryptions per cookie byte.
+//
+// The fake oracle only tests the attack itself. To recover the cookie from real
+// RC4 biases, which needs about 2^24 encryptions per cookie byte and took
+// about 11 minutes on a single core, run:
+//
+//	go test ./set7 -run 56 -chal56 real,24 -timeout 60m
+var chal56Params = flag.String("chal56", "fake,13", "Parameters for challenge 56")
+
+var challenge56Cookie = testutil.MustBase64DecodeString("QkUgU1VSRSBUTyBEUklOSyBZT1VSIE9WQUxUSU5F")
+
+func TestChallenge56(t *testing.T) {
+	fake, log2Samples, err := parseRC4BiasTestParams(*chal56Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oracle := RC4CookieOracle
+	if fake {
+		oracle = FakeRC4CookieOracle
+	}
+
+	got, err := RecoverCookieFromRC4Biases(oracle, 1<<log2Samples, runtime.NumCPU(), t.Logf)
+	if err != nil {
+		t.Fatalf("recovering cookie: %v", err)
+	}
+	if got != string(challenge56Cookie) {
+		t.Fatalf("want: '%s', got: '%s'", challenge56Cookie, got)
+	}
+}
+
+// RC4CookieOracle encrypts request || cookie with RC4 under a fresh 128-bit
+// key.
+func RC4CookieOracle(request []byte) []byte {
+	plaintext := append(append([]byte(nil), request...), challenge56Cookie...)
+	return testutil.Must(cipher.RC4Crypt(plaintext, testutil.MustRandomBytes(16)))
+}
+
+// FakeRC4CookieOracle encrypts request || cookie with a keystream whose
+// biases at z16 and z32 are greatly exaggerated, so that the attack can be
+// tested quickly.
+func FakeRC4CookieOracle(request []byte) []byte {
+	return testutil.FakeRC4Encrypt(append(append([]byte(nil), request...), challenge56Cookie...))
+}
+
+func parseRC4BiasTestParams(s string) (fake bool, log2Samples int, err error) {
+	args := strings.Split(s, ",")
+	if len(args) != 2 {
+		return false, 0, errors.New("wrong number of RC4 bias test parameters")
+	}
+	switch args[0] {
+	case "real":
+		fake = false
+	case "fake":
+		fake = true
+	default:
+		return false, 0, errors.New("invalid oracle type value: must be 'real' or 'fake'")
+	}
+	if log2Samples, err = strconv.Atoi(args[1]); err != nil {
+		return false, 0, fmt.Errorf("invalid sample count value: %w", err)
+	}
+	return fake, log2Samples, nil
+}
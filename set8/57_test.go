@@ -0,0 +1,42 @@
+package set8
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/internal/testutil"
+)
+
+func TestChallenge57(t *testing.T) {
+	group := Challenge57Group()
+	if got := new(big.Int).Exp(group.G, group.Q, group.P); got.Cmp(big.NewInt(1)) != 0 {
+		t.Fatalf("want g^q = 1 mod p, got: %d", got)
+	}
+
+	victim := NewDHMACVictim(group)
+	got, err := RecoverDHKeyFromSmallSubgroups(group, victim.Respond, t.Logf)
+	if err != nil {
+		t.Fatalf("recovering key: %v", err)
+	}
+	if victim.Key.X.Cmp(got) != 0 {
+		t.Fatalf("want: %d, got: %d", victim.Key.X, got)
+	}
+}
+
+// DHMACVictim responds to any public key it is sent with a message and the MAC
+// of that message, keyed by the shared secret. It does not validate the public
+// key.
+type DHMACVictim struct {
+	Key *dh.PrivateKey
+}
+
+func NewDHMACVictim(group *dh.Group) *DHMACVictim {
+	return &DHMACVictim{Key: testutil.Must(dh.GenerateKey(group, rand.Reader))}
+}
+
+func (v *DHMACVictim) Respond(publicKey *big.Int) (message, mac []byte, err error) {
+	message = []byte("crazy flamboyant for the rap enjoyment")
+	return message, MACSharedSecret(v.Key.SharedSecret(publicKey), message), nil
+}
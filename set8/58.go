@@ -0,0 +1,123 @@
+// # Pollard's Method for Catching Kangaroos
+//
+// The last problem was a little contrived. It only worked because I
+// hand-selected parameters to make it work. In reality, p-1 will rarely have
+// so many small factors, let alone enough of them to sum to q.
+//
+// So what can we do when there are only a few small factors?
+//
+// Consider this group:
+//
+//	p = 11470374874925275658116663507232161402086650258453896274534991676898999262641581519101074740642369848233294239851519212341844337347119899874391456329785623
+//	q = 335062023296420808191071248367701059461
+//	j = 34233586850807404623475048381328686211071196701374230492615844865929237417097514638999377942356150481334217896204702
+//	g = 622952335333961296978159266084741085889881358738459939978290179936063635566740258555167783009058567397963466103140082647486611657350811560630587013183357
+//
+// Once again, j has small factors, but not enough of them. We can still
+// recover x mod r for each of them, as in the last problem, but the product
+// of the r's will fall well short of q.
+//
+// What's the next step? We know:
+//
+//	x = n mod r
+//
+// Where n and r are the combined residue and modulus we learned. This means:
+//
+//	x = n + m*r
+//
+// For some unknown m. We're not sure what m is, but we do know its range.
+// Since x < q:
+//
+//	0 <= m <= (q-1)/r
+//
+// So we need to search over a known range of values for m. Enter Pollard's
+// kangaroo algorithm, which can find the discrete log of an element in a
+// known interval [a, b] in about sqrt(b-a) time.
+//
+// It goes roughly like this: a tame kangaroo starts at g^b and takes N
+// pseudorandom jumps, determined by a function f of its current position,
+// laying a trap where it lands. A wild kangaroo then starts at y = g^x and
+// jumps according to the same function. If it ever lands on the tame
+// kangaroo's path, it follows it into the trap, and since we know how far each
+// kangaroo traveled, we can solve for x. If instead the wild kangaroo travels
+// past the trap, we give up.
+//
+// Use f(y) = 2^(y mod k), for some k based on a - b, and choose N to be 4
+// times the mean of f.
+//
+// Implement Pollard's kangaroo algorithm. Here are some (less accommodating)
+// group parameters:
+//
+//	p = 11470374874925275658116663507232161402086650258453896274534991676898999262641581519101074740642369848233294239851519212341844337347119899874391456329785623
+//	g = 622952335333961296978159266084741085889881358738459939978290179936063635566740258555167783009058567397963466103140082647486611657350811560630587013183357
+//
+// And here's a sample y:
+//
+//	y = 7760073848032689505395005705677365876654629189298052775754597607446617558600394076764814236081991643094239886772481052254010323780165093955236429914607119
+//
+// The index of y is in the range [0, 2^20]. Find it with the kangaroo
+// algorithm.
+//
+// Wait, that's small enough to brute force. Here's its big brother:
+//
+//	y = 9388897478013399550694114614498790691034187453089355259602614074132918843899833277397448144245883225611726912025846772975325932794909655215329941809013733
+//
+// Its index is in the range [0, 2^40]. Find it with the kangaroo algorithm.
+//
+// Now, back to the original problem. Recover Bob's secret key x mod r for
+// each small factor r of j, as in the last problem. Then transform the
+// public key y into y' = y * g^-n = g^(m*r), and use the kangaroo algorithm
+// with generator g' = g^r to find m in [0, (q-1)/r]. Finally, reassemble
+// x = n + m*r.
+
+package set8
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/saclark/cryptopals/attack"
+	"github.com/saclark/cryptopals/dh"
+)
+
+const (
+	challenge58P = "11470374874925275658116663507232161402086650258453896274534991676898999262641581519101074740642369848233294239851519212341844337347119899874391456329785623"
+	challenge58G = "622952335333961296978159266084741085889881358738459939978290179936063635566740258555167783009058567397963466103140082647486611657350811560630587013183357"
+	challenge58Q = "335062023296420808191071248367701059461"
+)
+
+// Challenge58Group returns the group given in the challenge, in which G has
+// prime order Q but (P-1)/Q has too few small factors to recover a private key
+// from them alone. A new Group is returned on each call, so callers are free
+// to modify it.
+func Challenge58Group() *dh.Group {
+	p, _ := new(big.Int).SetString(challenge58P, 10)
+	g, _ := new(big.Int).SetString(challenge58G, 10)
+	q, _ := new(big.Int).SetString(challenge58Q, 10)
+	return &dh.Group{P: p, G: g, Q: q}
+}
+
+// RecoverDHKeyWithKangaroo recovers a victim's DH private key in group, given
+// its public key y, by sending it public keys confined to small subgroups, as
+// RecoverDHKeyFromSmallSubgroups does, and then finding the rest of the key
+// with Pollard's kangaroo algorithm.
+//
+// When not nil, logf is used to log the attack's progress.
+func RecoverDHKeyWithKangaroo(
+	group *dh.Group,
+	y *big.Int,
+	query func(publicKey *big.Int) (message, mac []byte, err error),
+	logf func(format string, a ...any),
+) (*big.Int, error) {
+	n, r, err := attack.ExploitSmallSubgroupConfinement(group, maxSubgroupOrder, macOracle(query), logf)
+	if err != nil {
+		return nil, fmt.Errorf("exploiting small subgroups: %w", err)
+	}
+
+	x, err := attack.RecoverDHKeyFromResidue(group, y, n, r, logf)
+	if err != nil {
+		return nil, fmt.Errorf("finding key from residue: %w", err)
+	}
+
+	return x, nil
+}
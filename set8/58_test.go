@@ -0,0 +1,38 @@
+package set8
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/saclark/cryptopals/attack"
+)
+
+func TestChallenge58_Kangaroo(t *testing.T) {
+	group := Challenge58Group()
+	y, _ := new(big.Int).SetString("7760073848032689505395005705677365876654629189298052775754597607446617558600394076764814236081991643094239886772481052254010323780165093955236429914607119", 10)
+	a, b := big.NewInt(0), big.NewInt(1<<20)
+	k, n := attack.KangarooParams(b)
+
+	got, jumps, err := attack.DiscreteLogInRange(group.P, group.G, y, a, b, k, n)
+	if err != nil {
+		t.Fatalf("finding discrete log: %v", err)
+	}
+	t.Logf("k = %d, n = %d: %d jumps, expected at most ~%d", k, n, jumps, attack.ExpectedKangarooJumps(b, k, n))
+
+	if want := big.NewInt(705485); want.Cmp(got) != 0 {
+		t.Fatalf("want: %d, got: %d", want, got)
+	}
+}
+
+func TestChallenge58(t *testing.T) {
+	group := Challenge58Group()
+	victim := NewDHMACVictim(group)
+
+	got, err := RecoverDHKeyWithKangaroo(group, victim.Key.Y, victim.Respond, t.Logf)
+	if err != nil {
+		t.Fatalf("recovering key: %v", err)
+	}
+	if victim.Key.X.Cmp(got) != 0 {
+		t.Fatalf("want: %d, got: %d", victim.Key.X, got)
+	}
+}
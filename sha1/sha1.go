@@ -3,6 +3,8 @@ package sha1
 import (
 	"encoding/binary"
 	"math/bits"
+
+	"github.com/saclark/cryptopals/md"
 )
 
 const (
@@ -27,13 +29,22 @@ const BlockSize = 64
 // Digest is a SHA-1 message digest.
 type Digest [Size]byte
 
+// hash is SHA-1 built from its compression function.
+var hash = &md.Hash{
+	BlockSize: BlockSize,
+	IV:        stateBytes([5]uint32{_h0, _h1, _h2, _h3, _h4}),
+	Compress:  Compress,
+	Pad:       md.LengthPadding(BlockSize, binary.BigEndian),
+}
+
 // Sum returns the SHA-1 checksum of message.
 //
 // A proper implementation exists in the Go standard library. This was written
 // as a learning exercise.
 func Sum(message []byte) Digest {
-	h := [5]uint32{_h0, _h1, _h2, _h3, _h4}
-	return SumFromHashState(h, 0, message)
+	var digest Digest
+	copy(digest[:], hash.Sum(message))
+	return digest
 }
 
 // SumFromHashState returns the SHA-1 checksum of the data starting from an
@@ -41,74 +52,75 @@ func Sum(message []byte) Digest {
 // initLen. The message bit length written to the padding is
 // (initLen + len(data)) * 8.
 func SumFromHashState(h [5]uint32, initLen uint64, data []byte) Digest {
-	dataLen := len(data)
-	padLen := BlockSize - (dataLen % BlockSize)
-	if padLen < 9 {
-		padLen += BlockSize
-	}
+	var digest Digest
+	copy(digest[:], hash.SumFromState(stateBytes(h), initLen, data))
+	return digest
+}
 
-	m := make([]byte, dataLen+padLen)
-	copy(m, data)
-	m[dataLen] = 0x80
-	binary.BigEndian.PutUint64(m[len(m)-8:], (initLen+uint64(dataLen))*8)
+// Compress is the SHA-1 compression function. It returns the state of the hash
+// registers, serialized big-endian, after processing a single 64-byte block.
+func Compress(state, block []byte) []byte {
+	var h [5]uint32
+	for i := range h {
+		h[i] = binary.BigEndian.Uint32(state[i*4:])
+	}
 
 	var w [80]uint32
-	for ; len(m) >= BlockSize; m = m[BlockSize:] {
-		for i, j := 0, 0; i < 16; i, j = i+1, j+4 {
-			w[i] = binary.BigEndian.Uint32(m[j : j+4])
-		}
+	for i, j := 0, 0; i < 16; i, j = i+1, j+4 {
+		w[i] = binary.BigEndian.Uint32(block[j : j+4])
+	}
 
-		for t := 16; t < 80; t++ {
-			w[t] = bits.RotateLeft32((w[t-3] ^ w[t-8] ^ w[t-14] ^ w[t-16]), 1)
-		}
+	for t := 16; t < 80; t++ {
+		w[t] = bits.RotateLeft32((w[t-3] ^ w[t-8] ^ w[t-14] ^ w[t-16]), 1)
+	}
 
-		var (
-			a = h[0]
-			b = h[1]
-			c = h[2]
-			d = h[3]
-			e = h[4]
-		)
-
-		var f, k uint32
-		for t := 0; t < 80; t++ {
-			switch {
-			case t < 20:
-				f = (b & c) | (^b & d)
-				k = _k0
-			case t < 40:
-				f = b ^ c ^ d
-				k = _k1
-			case t < 60:
-				f = (b & c) | (b & d) | (c & d)
-				k = _k2
-			case t < 80:
-				f = b ^ c ^ d
-				k = _k3
-			}
-
-			tmp := (bits.RotateLeft32(a, 5) + f + e + w[t] + k)
-
-			e = d
-			d = c
-			c = bits.RotateLeft32(b, 30)
-			b = a
-			a = tmp
+	var (
+		a = h[0]
+		b = h[1]
+		c = h[2]
+		d = h[3]
+		e = h[4]
+	)
+
+	var f, k uint32
+	for t := 0; t < 80; t++ {
+		switch {
+		case t < 20:
+			f = (b & c) | (^b & d)
+			k = _k0
+		case t < 40:
+			f = b ^ c ^ d
+			k = _k1
+		case t < 60:
+			f = (b & c) | (b & d) | (c & d)
+			k = _k2
+		case t < 80:
+			f = b ^ c ^ d
+			k = _k3
 		}
 
-		h[0] += a
-		h[1] += b
-		h[2] += c
-		h[3] += d
-		h[4] += e
+		tmp := (bits.RotateLeft32(a, 5) + f + e + w[t] + k)
+
+		e = d
+		d = c
+		c = bits.RotateLeft32(b, 30)
+		b = a
+		a = tmp
 	}
 
-	var md Digest
-	binary.BigEndian.PutUint32(md[0:], h[0])
-	binary.BigEndian.PutUint32(md[4:], h[1])
-	binary.BigEndian.PutUint32(md[8:], h[2])
-	binary.BigEndian.PutUint32(md[12:], h[3])
-	binary.BigEndian.PutUint32(md[16:], h[4])
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+	h[4] += e
 
-	return md
+	return stateBytes(h)
+}
+
+func stateBytes(h [5]uint32) []byte {
+	state := make([]byte, Size)
+	for i, v := range h {
+		binary.BigEndian.PutUint32(state[i*4:], v)
+	}
+	return state
 }
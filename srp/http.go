@@ -0,0 +1,144 @@
+package srp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+)
+
+type startRequest struct {
+	Email string   `json:"email"`
+	A     *big.Int `json:"A"`
+}
+
+type startResponse struct {
+	Salt []byte   `json:"salt"`
+	B    *big.Int `json:"B"`
+}
+
+type verifyRequest struct {
+	Email string `json:"email"`
+	Proof []byte `json:"proof"`
+}
+
+// NewHandler returns an http.Handler that exposes auth over HTTP. Logins are
+// started by POSTing a JSON object with "email" and "A" fields to /start,
+// which responds with a JSON object containing "salt" and "B" fields. They are
+// completed by POSTing a JSON object with "email" and "proof" fields to
+// /verify, which responds with a 200 if the proof is valid and a 401 if not.
+func NewHandler(auth Authenticator) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req startRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.A == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		salt, B, err := auth.Start(r.Context(), req.Email, req.A)
+		if err != nil {
+			w.WriteHeader(statusCode(err))
+			return
+		}
+
+		w.Header().Add("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(startResponse{Salt: salt, B: B})
+	})
+	mux.HandleFunc("/verify", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var req verifyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := auth.Verify(r.Context(), req.Email, req.Proof); err != nil {
+			w.WriteHeader(statusCode(err))
+			return
+		}
+	})
+	return mux
+}
+
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, ErrAuthenticationFailed), errors.Is(err, ErrUnknownUser):
+		return http.StatusUnauthorized
+	case errors.Is(err, ErrInvalidPublicKey):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// HTTPAuthenticator is an Authenticator that logs in against a server exposed
+// by NewHandler at BaseURL.
+type HTTPAuthenticator struct {
+	Client  *http.Client
+	BaseURL string
+}
+
+// Start implements Authenticator.
+func (a *HTTPAuthenticator) Start(ctx context.Context, email string, A *big.Int) (salt []byte, B *big.Int, err error) {
+	var resp startResponse
+	if err := a.post(ctx, "/start", startRequest{Email: email, A: A}, &resp); err != nil {
+		return nil, nil, err
+	}
+	if resp.B == nil {
+		return nil, nil, errors.New("missing B in response")
+	}
+	return resp.Salt, resp.B, nil
+}
+
+// Verify implements Authenticator.
+func (a *HTTPAuthenticator) Verify(ctx context.Context, email string, proof []byte) error {
+	return a.post(ctx, "/verify", verifyRequest{Email: email, Proof: proof}, nil)
+}
+
+func (a *HTTPAuthenticator) post(ctx context.Context, path string, body, result any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", a.BaseURL+path, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Add("Content-Type", "application/json")
+
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case 200:
+	case 401:
+		return ErrAuthenticationFailed
+	default:
+		return fmt.Errorf("unexpected response status code: %d", resp.StatusCode)
+	}
+
+	if result == nil {
+		_, err = io.Copy(io.Discard, resp.Body)
+		return err
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+package srp
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/saclark/cryptopals/dh"
+	"github.com/saclark/cryptopals/hmac"
+)
+
+// The size of a salt generated by NewVerifier, in bytes.
+const SaltSize = 16
+
+var (
+	// ErrAuthenticationFailed is returned when a login proof is invalid or
+	// there is no login in progress.
+	ErrAuthenticationFailed = errors.New("srp: authentication failed")
+	// ErrUnknownUser is returned when starting a login for an unregistered
+	// email.
+	ErrUnknownUser = errors.New("srp: unknown user")
+	// ErrInvalidPublicKey is returned when a peer's public ephemeral value is
+	// congruent to 0 mod N.
+	ErrInvalidPublicKey = errors.New("srp: invalid public key")
+)
+
+// Params are the public parameters agreed upon by clients and servers: a
+// large safe prime, N, and a generator, G.
+type Params struct {
+	N *big.Int
+	G *big.Int
+}
+
+// NISTParams returns the 1536-bit MODP prime from RFC 3526 with a generator of
+// 2. New Params are returned on each call, so callers are free to modify them.
+func NISTParams() *Params {
+	g := dh.NISTGroup()
+	return &Params{N: g.P, G: g.G}
+}
+
+// Multiplier returns the SRP-6a multiplier parameter k = H(N | PAD(g)).
+func (p *Params) Multiplier() *big.Int {
+	return hashInts(p, p.N, p.G)
+}
+
+// Scramble returns the SRP-6a scrambling parameter u = H(PAD(A) | PAD(B)).
+func (p *Params) Scramble(A, B *big.Int) *big.Int {
+	return hashInts(p, A, B)
+}
+
+// NewVerifier generates a random salt for password and returns the salt along
+// with the password verifier v = g^x mod N, where x = H(salt | password).
+func NewVerifier(params *Params, password []byte, rand io.Reader) (salt []byte, v *big.Int, err error) {
+	salt = make([]byte, SaltSize)
+	if _, err := io.ReadFull(rand, salt); err != nil {
+		return nil, nil, fmt.Errorf("generating salt: %w", err)
+	}
+	x := PrivateKey(salt, password)
+	return salt, new(big.Int).Exp(params.G, x, params.N), nil
+}
+
+// PrivateKey returns x = H(salt | password).
+func PrivateKey(salt, password []byte) *big.Int {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write(password)
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
+
+// SessionKey returns the session key K = H(S) for the premaster secret S.
+func SessionKey(S *big.Int) []byte {
+	sum := sha256.Sum256(S.Bytes())
+	return sum[:]
+}
+
+// Proof returns HMAC-SHA256(K, salt), which a client sends to prove to the
+// server that it derived the same session key.
+func Proof(K, salt []byte) []byte {
+	return hmac.New(hmac.SHA256{}, K).Sum(salt)
+}
+
+// Authenticator is the server side of an SRP login, which a client drives in
+// two steps.
+type Authenticator interface {
+	// Start begins a login for email with the client's public ephemeral value,
+	// A. It returns the user's salt and the server's public ephemeral value, B.
+	Start(ctx context.Context, email string, A *big.Int) (salt []byte, B *big.Int, err error)
+	// Verify completes the login for email, returning nil if proof shows the
+	// client derived the same session key as the server.
+	Verify(ctx context.Context, email string, proof []byte) error
+}
+
+// Login authenticates email with password against auth, reading random bytes
+// from rand, which will typically be crypto/rand.Reader.
+func Login(
+	ctx context.Context,
+	auth Authenticator,
+	params *Params,
+	email string,
+	password []byte,
+	rand io.Reader,
+) error {
+	a, err := randomExponent(params, rand)
+	if err != nil {
+		return err
+	}
+	A := new(big.Int).Exp(params.G, a, params.N)
+
+	salt, B, err := auth.Start(ctx, email, A)
+	if err != nil {
+		return fmt.Errorf("starting login: %w", err)
+	}
+	if new(big.Int).Mod(B, params.N).Sign() == 0 {
+		return ErrInvalidPublicKey
+	}
+
+	u := params.Scramble(A, B)
+	if u.Sign() == 0 {
+		return ErrInvalidPublicKey
+	}
+
+	// S = (B - k * g^x)^(a + u * x) mod N
+	x := PrivateKey(salt, password)
+	base := new(big.Int).Exp(params.G, x, params.N)
+	base.Mul(base, params.Multiplier())
+	base.Sub(B, base)
+	base.Mod(base, params.N)
+	exp := new(big.Int).Mul(u, x)
+	exp.Add(exp, a)
+	S := new(big.Int).Exp(base, exp, params.N)
+
+	if err := auth.Verify(ctx, email, Proof(SessionKey(S), salt)); err != nil {
+		return fmt.Errorf("verifying login: %w", err)
+	}
+	return nil
+}
+
+// Server is an in-process SRP server. It is safe for concurrent use. Only one
+// login may be in progress per user at a time; starting a new login abandons
+// the previous one.
+type Server struct {
+	params *Params
+	rand   io.Reader
+
+	mu       sync.Mutex
+	users    map[string]verifier
+	sessions map[string][]byte
+}
+
+type verifier struct {
+	salt []byte
+	v    *big.Int
+}
+
+// NewServer returns a Server that reads random bytes from rand, which will
+// typically be crypto/rand.Reader.
+func NewServer(params *Params, rand io.Reader) *Server {
+	return &Server{
+		params:   params,
+		rand:     rand,
+		users:    map[string]verifier{},
+		sessions: map[string][]byte{},
+	}
+}
+
+// Register stores a salt and password verifier for email, replacing any
+// previously registered password.
+func (s *Server) Register(email string, password []byte) error {
+	salt, v, err := NewVerifier(s.params, password, s.rand)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[email] = verifier{salt: salt, v: v}
+	return nil
+}
+
+// Start implements Authenticator.
+func (s *Server) Start(ctx context.Context, email string, A *big.Int) (salt []byte, B *big.Int, err error) {
+	s.mu.Lock()
+	user, ok := s.users[email]
+	s.mu.Unlock()
+	if !ok {
+		return nil, nil, ErrUnknownUser
+	}
+
+	if new(big.Int).Mod(A, s.params.N).Sign() == 0 {
+		return nil, nil, ErrInvalidPublicKey
+	}
+
+	b, err := randomExponent(s.params, s.rand)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// B = k * v + g^b mod N
+	B = new(big.Int).Exp(s.params.G, b, s.params.N)
+	kv := new(big.Int).Mul(s.params.Multiplier(), user.v)
+	B.Add(B, kv)
+	B.Mod(B, s.params.N)
+
+	// S = (A * v^u)^b mod N
+	u := s.params.Scramble(A, B)
+	S := new(big.Int).Exp(user.v, u, s.params.N)
+	S.Mul(S, A)
+	S.Exp(S, b, s.params.N)
+
+	s.mu.Lock()
+	s.sessions[email] = Proof(SessionKey(S), user.salt)
+	s.mu.Unlock()
+
+	return user.salt, B, nil
+}
+
+// Verify implements Authenticator.
+func (s *Server) Verify(ctx context.Context, email string, proof []byte) error {
+	s.mu.Lock()
+	want, ok := s.sessions[email]
+	delete(s.sessions, email)
+	s.mu.Unlock()
+
+	if !ok || subtle.ConstantTimeCompare(want, proof) != 1 {
+		return ErrAuthenticationFailed
+	}
+	return nil
+}
+
+// randomExponent returns a random private ephemeral value in [1, N-1].
+func randomExponent(params *Params, rand io.Reader) (*big.Int, error) {
+	max := new(big.Int).Sub(params.N, big.NewInt(1))
+	n, err := cryptorand.Int(rand, max)
+	if err != nil {
+		return nil, fmt.Errorf("generating private ephemeral value: %w", err)
+	}
+	return n.Add(n, big.NewInt(1)), nil
+}
+
+// hashInts returns the SHA-256 checksum of the concatenation of each value,
+// left padded with zeros to the byte length of N, as an integer. Values longer
+// than N are hashed as-is.
+func hashInts(params *Params, values ...*big.Int) *big.Int {
+	size := (params.N.BitLen() + 7) / 8
+	h := sha256.New()
+	for _, v := range values {
+		b := v.Bytes()
+		if len(b) < size {
+			h.Write(make([]byte, size-len(b)))
+		}
+		h.Write(b)
+	}
+	return new(big.Int).SetBytes(h.Sum(nil))
+}
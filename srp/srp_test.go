@@ -0,0 +1,77 @@
+package srp
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLogin(t *testing.T) {
+	server := NewServer(NISTParams(), rand.Reader)
+	if err := server.Register("alice@example.com", []byte("hunter2")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	ts := httptest.NewServer(NewHandler(server))
+	defer ts.Close()
+
+	tt := []struct {
+		name string
+		auth Authenticator
+	}{
+		{"in-process", server},
+		{"http", &HTTPAuthenticator{Client: ts.Client(), BaseURL: ts.URL}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			err := Login(ctx, tc.auth, NISTParams(), "alice@example.com", []byte("hunter2"), rand.Reader)
+			if err != nil {
+				t.Fatalf("want successful login, got: %v", err)
+			}
+
+			err = Login(ctx, tc.auth, NISTParams(), "alice@example.com", []byte("hunter3"), rand.Reader)
+			if !errors.Is(err, ErrAuthenticationFailed) {
+				t.Fatalf("want: %v, got: %v", ErrAuthenticationFailed, err)
+			}
+
+			err = Login(ctx, tc.auth, NISTParams(), "mallory@example.com", []byte("hunter2"), rand.Reader)
+			if err == nil {
+				t.Fatal("want failed login for unknown user, got nil")
+			}
+		})
+	}
+}
+
+func TestServerStart_RejectsZeroPublicKey(t *testing.T) {
+	params := NISTParams()
+	server := NewServer(params, rand.Reader)
+	if err := server.Register("alice@example.com", []byte("hunter2")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	for i := int64(0); i < 3; i++ {
+		A := new(big.Int).Mul(params.N, big.NewInt(i))
+		_, _, err := server.Start(context.Background(), "alice@example.com", A)
+		if !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("A = %d * N: want: %v, got: %v", i, ErrInvalidPublicKey, err)
+		}
+	}
+}
+
+func TestServerVerify_RequiresLoginInProgress(t *testing.T) {
+	server := NewServer(NISTParams(), rand.Reader)
+	if err := server.Register("alice@example.com", []byte("hunter2")); err != nil {
+		t.Fatalf("registering user: %v", err)
+	}
+
+	err := server.Verify(context.Background(), "alice@example.com", Proof(SessionKey(big.NewInt(0)), nil))
+	if !errors.Is(err, ErrAuthenticationFailed) {
+		t.Fatalf("want: %v, got: %v", ErrAuthenticationFailed, err)
+	}
+}